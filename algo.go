@@ -40,6 +40,19 @@ func RayIntersection(start, delta HexCoord, set *HexSet) (HexCoord, error) {
 	}
 }
 
+// SearchParams provides parameters for an uninformed search (like
+// BreadthFirstSearch) starting from a set of coordinates. If Stats is set,
+// it is filled in whether or not the search succeeds. If Tracer is set, it
+// is notified of the progress of the search.
+type SearchParams struct {
+	Start       *HexSet
+	IsSteppable func(HexCoord, HexCoord) bool
+	IsGoal      func(HexCoord) bool
+	Limits      SearchLimits
+	Stats       *SearchStats
+	Tracer      SearchTracer
+}
+
 // BreadthFirstSearch runs a breadth-first search through a hex grid. The hex grid is
 // specified through the two functions "isSteppable" (which determines whether a step from
 // one coordinate to another is legal) and "isGoal" (which determines whether the goal
@@ -51,42 +64,18 @@ func BreadthFirstSearch(start HexCoord, isSteppable func(HexCoord, HexCoord) boo
 // BreadthFirstSearchFromMultiple performs a breadth-first search (like BreadthFirstSearch),
 // except starting from multiple coordinates at once.
 func BreadthFirstSearchFromMultiple(frontierSet *HexSet, isSteppable func(HexCoord, HexCoord) bool, isGoal func(HexCoord) bool) ([]HexCoord, error) {
-	frontier := frontierSet.ToList()
-	trail := map[HexCoord]*HexCoord{}
-	for _, p := range frontier {
-		trail[p] = nil
-	}
-
-	for {
-		if len(frontier) == 0 {
-			return nil, fmt.Errorf("no path to goal")
-		}
-
-		parent := frontier[0]
-		frontier = frontier[1:]
-
-		if isGoal(parent) {
-			path := []HexCoord{}
-			node := parent
-
-			for {
-				path = append([]HexCoord{node}, path...)
-				if trail[node] == nil {
-					return path, nil
-				}
-				node = *trail[node]
-			}
-		}
+	return BreadthFirstSearchWithParams(&SearchParams{
+		Start:       frontierSet,
+		IsSteppable: isSteppable,
+		IsGoal:      isGoal,
+	})
+}
 
-		for _, nb := range parent.Neighbours() {
-			_, seen := trail[nb]
-			if seen || !isSteppable(parent, nb) {
-				continue
-			}
-			trail[nb] = &parent
-			frontier = append(frontier, nb)
-		}
-	}
+// BreadthFirstSearchWithParams performs a breadth-first search (like
+// BreadthFirstSearch), bounded by the limits in the SearchParams. Errors are
+// ErrNoPath, ErrSearchBudget or the error of the context in Limits.
+func BreadthFirstSearchWithParams(params *SearchParams) ([]HexCoord, error) {
+	return uninformedSearch(params, BreadthFirst)
 }
 
 // HexSetWithHolesFilled returns a HexSet with any internal holes filled.
@@ -203,39 +192,49 @@ func FindConnectedComponent(start HexCoord, isContained func(HexCoord) bool) (*H
 // DepthFirstSearch performs a depth-first search on a hex grid. Like BreadthFirstSearch, the
 // hex grid is specified through callback functions "isSteppable" and "isGoal".
 func DepthFirstSearch(start HexCoord, isSteppable func(HexCoord, HexCoord) bool, isGoal func(HexCoord) bool) ([]HexCoord, error) {
-	trail := map[HexCoord]*HexCoord{start: nil}
-	frontier := []HexCoord{start}
-
-	for {
-		if len(frontier) == 0 {
-			return nil, fmt.Errorf("no path to goal")
-		}
+	return DepthFirstSearchWithParams(&SearchParams{
+		Start:       NewHexSetSingleton(start),
+		IsSteppable: isSteppable,
+		IsGoal:      isGoal,
+	})
+}
 
-		parent := frontier[0]
-		frontier = frontier[1:]
+// DepthFirstSearchWithParams performs a depth-first search (like
+// DepthFirstSearch), bounded by the limits in the SearchParams. Errors are
+// ErrNoPath, ErrSearchBudget or the error of the context in Limits.
+func DepthFirstSearchWithParams(params *SearchParams) ([]HexCoord, error) {
+	return uninformedSearch(params, DepthFirst)
+}
 
-		if isGoal(parent) {
-			path := []HexCoord{}
-			node := parent
+func uninformedSearch(params *SearchParams, order StateSearchOrder) ([]HexCoord, error) {
+	var tracer StateTracer[HexCoord]
+	if params.Tracer != nil {
+		tracer = params.Tracer
+	}
 
-			for {
-				path = append([]HexCoord{node}, path...)
-				if trail[node] == nil {
-					return path, nil
+	result, err := StateSearch(&StateSearchParams[HexCoord]{
+		Start:  params.Start.ToList(),
+		IsGoal: params.IsGoal,
+		Successors: func(p HexCoord) []StateStep[HexCoord] {
+			var rv []StateStep[HexCoord]
+			for _, d := range OrderedDirections {
+				nb := p.AddDelta(Directions[d])
+				if params.IsSteppable(p, nb) {
+					rv = append(rv, StateStep[HexCoord]{State: nb, Cost: 1, Action: int(d)})
 				}
-				node = *trail[node]
 			}
-		}
-
-		for _, nb := range parent.Neighbours() {
-			_, seen := trail[nb]
-			if seen || !isSteppable(parent, nb) {
-				continue
-			}
-			trail[nb] = &parent
-			frontier = append([]HexCoord{nb}, frontier...)
-		}
+			return rv
+		},
+		Order:  order,
+		Limits: params.Limits,
+		Stats:  params.Stats,
+		Tracer: tracer,
+	})
+	if err != nil {
+		return nil, err
 	}
+
+	return result.Path, nil
 }
 
 func sweepIntervalInSextant(n AngularInterval, section HexDir, r int, visit func(HexCoord)) error {
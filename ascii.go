@@ -0,0 +1,131 @@
+package hex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Hex diagrams are staggered ASCII drawings of hex grids. Each line of text
+// is one Y coordinate (decreasing downwards), and each X coordinate takes up
+// two columns of text (increasing rightwards). Since only positions where X
+// and Y have equal parity are hexes, the hexes on consecutive lines are
+// offset from each other:
+//
+//	  .   #
+//	#   #   .
+//	  #   #
+//
+// The characters '.' and ' ' represent hexes that are not present.
+
+const (
+	diagramEmpty  = '.'
+	diagramMember = '#'
+)
+
+// RenderHexDiagram draws a per-hex character map as a hex diagram. The
+// top-left character of the diagram is at the minimum X and maximum Y of the
+// map's HexCoords.
+func RenderHexDiagram(cells map[HexCoord]rune) string {
+	if len(cells) == 0 {
+		return ""
+	}
+
+	first := true
+	var minX, maxX, minY, maxY int
+	for p := range cells {
+		if first || p.X < minX {
+			minX = p.X
+		}
+		if first || p.X > maxX {
+			maxX = p.X
+		}
+		if first || p.Y < minY {
+			minY = p.Y
+		}
+		if first || p.Y > maxY {
+			maxY = p.Y
+		}
+		first = false
+	}
+
+	var lines []string
+	for y := maxY; y >= minY; y-- {
+		line := []rune(strings.Repeat(" ", 2*(maxX-minX)+1))
+		for x := minX; x <= maxX; x++ {
+			if (x+y)%2 != 0 {
+				continue
+			}
+			ch, ok := cells[NewHex(x, y)]
+			if !ok {
+				ch = diagramEmpty
+			}
+			line[2*(x-minX)] = ch
+		}
+		lines = append(lines, strings.TrimRight(string(line), " "))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Diagram draws the HexSet as a hex diagram, with the member hexes as '#'.
+func (h *HexSet) Diagram() string {
+	cells := map[HexCoord]rune{}
+	for _, p := range h.Enumerate() {
+		cells[p] = diagramMember
+	}
+	return RenderHexDiagram(cells)
+}
+
+// ParseHexDiagram reads a hex diagram into a per-hex character map. The
+// top-left character of the diagram (which need not be a hex) is at the
+// given coordinate. Empty lines at the start and end of the diagram, and tabs
+// at the start of each line, are ignored, so that diagrams can be written as
+// indented raw strings in Go source.
+func ParseHexDiagram(diagram string, topLeft HexCoord) (map[HexCoord]rune, error) {
+	lines := strings.Split(diagram, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(strings.TrimLeft(lines[i], "\t"), " \r")
+	}
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	rv := map[HexCoord]rune{}
+
+	for i, line := range lines {
+		for j, ch := range []rune(line) {
+			if ch == ' ' {
+				continue
+			}
+			x := topLeft.X + j/2
+			y := topLeft.Y - i
+			if j%2 != 0 || (x+y)%2 != 0 {
+				return nil, fmt.Errorf("character %q at line %d, column %d of hex diagram is not on a hex", ch, i+1, j+1)
+			}
+			if ch == diagramEmpty {
+				continue
+			}
+			rv[NewHex(x, y)] = ch
+		}
+	}
+
+	return rv, nil
+}
+
+// ParseHexSetDiagram reads a hex diagram into a HexSet containing all the
+// hexes that are present (i.e. not '.'), as with ParseHexDiagram.
+func ParseHexSetDiagram(diagram string, topLeft HexCoord) (*HexSet, error) {
+	cells, err := ParseHexDiagram(diagram, topLeft)
+	if err != nil {
+		return nil, err
+	}
+
+	rv := NewHexSet()
+	for p := range cells {
+		rv.Add(p)
+	}
+	return rv, nil
+}
@@ -0,0 +1,133 @@
+package hex
+
+import (
+	"testing"
+)
+
+func mustParseHexSetDiagram(t *testing.T, diagram string, topLeft HexCoord) *HexSet {
+	s, err := ParseHexSetDiagram(diagram, topLeft)
+	if err != nil {
+		t.Fatalf("failed to parse diagram: %v", err)
+	}
+	return s
+}
+
+func TestHexDiagramRoundTrip(t *testing.T) {
+	s := NewHexSetAround(NewHex(3, 1), 2)
+	s.RemoveHex(3, 1)
+	s.RemoveHex(4, 4)
+
+	rendered := s.Diagram()
+	rv := mustParseHexSetDiagram(t, rendered, NewHex(1, 5))
+	if !rv.Equals(s) {
+		t.Errorf("diagram round trip failed; rendered:\n%s\nparsed back:\n%s", rendered, rv.Diagram())
+	}
+}
+
+func TestRenderHexDiagram(t *testing.T) {
+	got := NewHexSetAround(Origin, 1).Except(Origin).Diagram()
+	expect := "  #\n#   #\n  .\n#   #\n  #"
+	if got != expect {
+		t.Errorf("expected diagram:\n%s\ngot:\n%s", expect, got)
+	}
+}
+
+func TestParseHexDiagramRejectsMisalignedCharacters(t *testing.T) {
+	if _, err := ParseHexDiagram("# #", Origin); err == nil {
+		t.Errorf("expected error for character on odd column")
+	}
+	if _, err := ParseHexDiagram("  #", Origin); err == nil {
+		t.Errorf("expected error for character on hex of wrong parity")
+	}
+}
+
+func TestHoleFillingDiagram(t *testing.T) {
+	s := mustParseHexSetDiagram(t, `
+		.   #   .
+		  .   #
+		#   #   #
+		  #   #
+		#   .   #
+		  #   .
+		#   #   #
+		  #   #
+		.   #   .
+	`, NewHex(-2, 4))
+	expect := mustParseHexSetDiagram(t, `
+		.   #   .
+		  .   #
+		#   #   #
+		  #   #
+		#   #   #
+		  #   #
+		#   #   #
+		  #   #
+		.   #   .
+	`, NewHex(-2, 4))
+
+	if got := HexSetWithHolesFilled(s); !got.Equals(expect) {
+		t.Errorf("expected holes filled as:\n%s\ngot:\n%s", expect.Diagram(), got.Diagram())
+	}
+}
+
+func TestOuterBorderDiagram(t *testing.T) {
+	cells, err := ParseHexDiagram(`
+		  o   .
+		o   o
+		  #   o
+		o   #
+		  o   o
+		o   #
+		  #   o
+		o   o
+		  o   .
+	`, NewHex(-1, 6))
+	if err != nil {
+		t.Fatalf("failed to parse diagram: %v", err)
+	}
+
+	s := NewHexSet()
+	expect := NewHexSet()
+	for p, ch := range cells {
+		if ch == '#' {
+			s.Add(p)
+		} else {
+			expect.Add(p)
+		}
+	}
+
+	if got := s.OuterBorder(); !got.Equals(expect) {
+		t.Errorf("expected outer border:\n%s\ngot:\n%s", expect.Diagram(), got.Diagram())
+	}
+}
+
+func TestFovDiagram(t *testing.T) {
+	cells, err := ParseHexDiagram(`
+		o   o   x   o   o
+		  o   .   .   o
+		o   o   x   o   o
+		  o   o   o   o
+		o   o   #   o   o
+		  o   o   o   o
+		o   o   @   o   o
+	`, NewHex(-4, 6))
+	if err != nil {
+		t.Fatalf("failed to parse diagram: %v", err)
+	}
+
+	lit := map[HexCoord]rune{}
+	obstruct := func(p HexCoord) bool { return cells[p] == '#' }
+	addLight := func(p HexCoord, _ AngularInterval) {
+		if _, ok := cells[p]; ok {
+			lit[p] = cells[p]
+		}
+	}
+	Origin.CalculateFov(FullAngularInterval, 6, obstruct, addLight)
+
+	for p, ch := range cells {
+		_, isLit := lit[p]
+		if (ch == 'x' && isLit) || (ch == 'o' && !isLit) {
+			t.Errorf("unexpected lighting at %v; lit hexes:\n%s", p, RenderHexDiagram(lit))
+		}
+	}
+}
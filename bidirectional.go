@@ -0,0 +1,179 @@
+package hex
+
+import (
+	"math"
+)
+
+// BidirectionalAStarParams provides parameters for a bidirectional A*
+// search between two hexes. Cost is as for AStarParams. Heuristic estimates
+// the cost of the cheapest path between two hexes; it must be consistent
+// (as well as admissible) in both directions, as straight-line distances are.
+type BidirectionalAStarParams struct {
+	Start     HexCoord
+	Goal      HexCoord
+	Cost      func(HexCoord, HexCoord) (float64, bool)
+	Heuristic func(HexCoord, HexCoord) float64
+	Limits    SearchLimits
+	Stats     *SearchStats
+}
+
+type biNode struct {
+	point     HexCoord
+	cost      float64
+	heuristic float64
+	parent    *biNode
+	closed    bool
+	seq       int
+	index     int
+}
+
+// biSide is one direction of a bidirectional search.
+type biSide struct {
+	open  *priorityQueue[*biNode]
+	nodes map[HexCoord]*biNode
+	// cost gives the cost of a step away from the root of this side.
+	cost      func(HexCoord, HexCoord) (float64, bool)
+	heuristic func(HexCoord) float64
+}
+
+func newBiSide(root HexCoord, cost func(HexCoord, HexCoord) (float64, bool), heuristic func(HexCoord) float64) *biSide {
+	less := func(a, b *biNode) bool {
+		if fa, fb := a.cost+a.heuristic, b.cost+b.heuristic; fa != fb {
+			return fa < fb
+		}
+		if a.cost != b.cost {
+			return a.cost > b.cost
+		}
+		return a.seq < b.seq
+	}
+	s := &biSide{
+		open:      newIndexedPriorityQueue(less, func(n *biNode, i int) { n.index = i }),
+		nodes:     map[HexCoord]*biNode{},
+		cost:      cost,
+		heuristic: heuristic,
+	}
+	node := &biNode{point: root, heuristic: heuristic(root)}
+	s.nodes[root] = node
+	s.open.Push(node)
+	return s
+}
+
+func (s *biSide) minEstimate() float64 {
+	if s.open.Len() == 0 {
+		return math.Inf(1)
+	}
+	n := s.open.Peek()
+	return n.cost + n.heuristic
+}
+
+// expand expands the best open node on this side, calling meet for every
+// node whose cost is improved.
+func (s *biSide) expand(seq *int, meet func(*biNode)) {
+	current := s.open.Pop()
+	current.closed = true
+
+	for _, nb := range current.point.Neighbours() {
+		stepCost, ok := s.cost(current.point, nb)
+		if !ok {
+			continue
+		}
+		cost := current.cost + stepCost
+
+		node, present := s.nodes[nb]
+		if present && (node.closed || cost >= node.cost) {
+			continue
+		}
+		if !present {
+			node = &biNode{point: nb, heuristic: s.heuristic(nb), seq: *seq}
+			*seq++
+			s.nodes[nb] = node
+		}
+		node.cost = cost
+		node.parent = current
+		if present {
+			s.open.Fix(node.index)
+		} else {
+			s.open.Push(node)
+		}
+		meet(node)
+	}
+}
+
+// BidirectionalAStar performs an A* search from both ends of a
+// point-to-point query at once, which typically expands far fewer nodes on
+// large open maps. Errors are ErrNoPath, ErrSearchBudget or the error of the
+// context in Limits.
+func BidirectionalAStar(params *BidirectionalAStarParams) (*AStarResult, error) {
+	monitor := newSearchMonitor(params.Limits, params.Stats)
+	defer monitor.finish()
+
+	forward := newBiSide(params.Start, params.Cost, func(p HexCoord) float64 {
+		return params.Heuristic(p, params.Goal)
+	})
+	backward := newBiSide(params.Goal, func(a, b HexCoord) (float64, bool) {
+		return params.Cost(b, a)
+	}, func(p HexCoord) float64 {
+		return params.Heuristic(params.Start, p)
+	})
+
+	best := math.Inf(1)
+	var meetForward, meetBackward *biNode
+	meet := func(other *biSide, forwards bool) func(*biNode) {
+		return func(n *biNode) {
+			o, ok := other.nodes[n.point]
+			if !ok || n.cost+o.cost >= best {
+				return
+			}
+			best = n.cost + o.cost
+			if forwards {
+				meetForward, meetBackward = n, o
+			} else {
+				meetForward, meetBackward = o, n
+			}
+		}
+	}
+
+	if params.Start == params.Goal {
+		best = 0
+		meetForward, meetBackward = forward.nodes[params.Start], backward.nodes[params.Goal]
+	}
+
+	seq := 1
+	for {
+		// Once either side cannot find anything cheaper, the best path
+		// found is optimal.
+		if forward.minEstimate() >= best || backward.minEstimate() >= best {
+			break
+		}
+		if forward.open.Len() == 0 || backward.open.Len() == 0 {
+			break
+		}
+		if err := monitor.expand(forward.open.Len() + backward.open.Len()); err != nil {
+			return nil, err
+		}
+
+		if forward.open.Len() <= backward.open.Len() {
+			forward.expand(&seq, meet(backward, true))
+		} else {
+			backward.expand(&seq, meet(forward, false))
+		}
+	}
+
+	if meetForward == nil {
+		return nil, ErrNoPath
+	}
+
+	rv := &AStarResult{Cost: best}
+	var rpath []HexCoord
+	for n := meetForward; n != nil; n = n.parent {
+		rpath = append(rpath, n.point)
+	}
+	for i := len(rpath) - 1; i >= 0; i-- {
+		rv.Path = append(rv.Path, rpath[i])
+	}
+	for n := meetBackward.parent; n != nil; n = n.parent {
+		rv.Path = append(rv.Path, n.point)
+	}
+
+	return rv, nil
+}
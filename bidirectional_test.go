@@ -0,0 +1,69 @@
+package hex
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestBidirectionalAStarMatchesAStar(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	region := NewHexSetAround(Origin, 12)
+	weights := map[HexCoord]float64{}
+	for _, p := range region.ToOrderedList() {
+		if r.Float64() < 0.25 {
+			continue
+		}
+		weights[p] = 1 + float64(r.Intn(3))
+	}
+	cost := func(a, b HexCoord) (float64, bool) {
+		w, ok := weights[b]
+		return w, ok
+	}
+	heuristic := func(a, b HexCoord) float64 { return float64(a.StepsTo(b)) }
+
+	for i := 0; i < 50; i++ {
+		start, _ := region.RandomFrom(r)
+		goal, _ := region.RandomFrom(r)
+		weights[start] = 1
+		weights[goal] = 1
+
+		expect, expectErr := AStar(&AStarParams{
+			Start:     NewHexSetSingleton(start),
+			IsGoal:    func(p HexCoord) bool { return p == goal },
+			Cost:      cost,
+			Heuristic: func(p HexCoord) float64 { return heuristic(p, goal) },
+		})
+		got, err := BidirectionalAStar(&BidirectionalAStarParams{
+			Start:     start,
+			Goal:      goal,
+			Cost:      cost,
+			Heuristic: heuristic,
+		})
+
+		if expectErr != nil {
+			if !errors.Is(err, ErrNoPath) {
+				t.Errorf("expected no path from %v to %v, got %v", start, goal, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("BidirectionalAStar from %v to %v failed: %v", start, goal, err)
+		}
+		if got.Cost != expect.Cost {
+			t.Errorf("expected cost %v from %v to %v, got %v", expect.Cost, start, goal, got.Cost)
+		}
+
+		total := 0.0
+		for j := 1; j < len(got.Path); j++ {
+			c, ok := cost(got.Path[j-1], got.Path[j])
+			if !ok || got.Path[j-1].StepsTo(got.Path[j]) != 1 {
+				t.Fatalf("invalid path %v", got.Path)
+			}
+			total += c
+		}
+		if got.Path[0] != start || got.Path[len(got.Path)-1] != goal || total != got.Cost {
+			t.Errorf("path %v does not match cost %v", got.Path, got.Cost)
+		}
+	}
+}
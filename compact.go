@@ -0,0 +1,117 @@
+package hex
+
+import (
+	"fmt"
+
+	pb "github.com/steinarvk/above-hex/hexpb"
+)
+
+const (
+	// maxCompactRunLength and maxCompactHexSetSize bound what a
+	// pb.CompactHexSet may decode to, so that a small malformed message
+	// cannot ask for an enormous set.
+	maxCompactRunLength  = 1 << 16
+	maxCompactHexSetSize = 1 << 24
+)
+
+// ToCompactProto converts a HexSet to a run-length encoded pb.CompactHexSet
+// proto. This is typically much smaller than ToProto for large, contiguous
+// sets. Sets of more than 2^24 hexes are encoded, but rejected by
+// HexSetFromCompactProto.
+func (h *HexSet) ToCompactProto() *pb.CompactHexSet {
+	rv := pb.CompactHexSet{}
+
+	var prevX, prevY, prevEnd int
+	var runX, runY, runLen int
+
+	// flush writes out the current run, split into runs no longer than the
+	// decoder accepts; each continues the same column with dx=0, dy=0.
+	flush := func() {
+		for runLen > 0 {
+			n := runLen
+			if n > maxCompactRunLength {
+				n = maxCompactRunLength
+			}
+			dx := runX - prevX
+			dy := runY - prevY
+			if dx == 0 && len(rv.Runs) > 0 {
+				dy = runY - (prevEnd + 2)
+			}
+			rv.Runs = append(rv.Runs, int32(dx), int32(dy), int32(n))
+			prevX = runX
+			prevY = runY
+			prevEnd = runY + 2*(n-1)
+			runY += 2 * n
+			runLen -= n
+		}
+	}
+
+	for _, p := range h.ToOrderedList() {
+		if runLen > 0 && p.X == runX && p.Y == runY+2*runLen {
+			runLen++
+			continue
+		}
+		flush()
+		runX, runY, runLen = p.X, p.Y, 1
+	}
+	flush()
+
+	return &rv
+}
+
+// HexSetFromCompactProto converts a pb.CompactHexSet proto to a HexSet. An
+// error is returned if the encoding is malformed, describes invalid
+// HexCoords, or describes a run longer than 65536 hexes or a set larger than
+// 2^24 hexes.
+func HexSetFromCompactProto(p *pb.CompactHexSet) (*HexSet, error) {
+	rv := NewHexSet()
+	if p == nil {
+		return rv, nil
+	}
+
+	if len(p.Runs)%3 != 0 {
+		return nil, fmt.Errorf("compact hex set has %d values, not a multiple of 3", len(p.Runs))
+	}
+
+	total := 0
+	for i := 2; i < len(p.Runs); i += 3 {
+		n := int(p.Runs[i])
+		if n < 1 || n > maxCompactRunLength {
+			return nil, fmt.Errorf("compact hex set has run of invalid length %d", n)
+		}
+		total += n
+	}
+	if total > maxCompactHexSetSize {
+		return nil, fmt.Errorf("compact hex set has too many hexes (%d)", total)
+	}
+
+	var x, y, end int
+	for i := 0; i < len(p.Runs); i += 3 {
+		dx, dy, n := int(p.Runs[i]), int(p.Runs[i+1]), int(p.Runs[i+2])
+
+		switch {
+		case dx < 0 && i > 0:
+			return nil, fmt.Errorf("compact hex set has runs out of order (dx=%d)", dx)
+		case dx == 0 && i > 0:
+			if dy < 0 {
+				return nil, fmt.Errorf("compact hex set has overlapping runs (dy=%d)", dy)
+			}
+			y = end + 2 + dy
+		default:
+			y += dy
+		}
+		x += dx
+
+		start, err := TryNewHex(x, y)
+		if err != nil {
+			return nil, fmt.Errorf("compact hex set has invalid hex (%d,%d): %v", x, y, err)
+		}
+
+		for j := 0; j < n; j++ {
+			rv.Add(start.AddMultDelta(j, Directions[North]))
+		}
+		end = y + 2*(n-1)
+	}
+
+	return rv, nil
+}
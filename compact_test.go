@@ -0,0 +1,82 @@
+package hex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	pb "github.com/steinarvk/above-hex/hexpb"
+)
+
+func TestCompactProtoRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(123))
+
+	sets := []*HexSet{NewHexSet(), NewHexSetAround(NewHex(-7, 3), 4)}
+	for i := 0; i < 20; i++ {
+		s := NewHexSet()
+		for _, p := range HexDisk(6) {
+			if r.Float64() < 0.6 {
+				s.Add(p.AddDelta(NewHex(3, -5)))
+			}
+		}
+		sets = append(sets, s)
+	}
+
+	for _, s := range sets {
+		rv, err := HexSetFromCompactProto(s.ToCompactProto())
+		if err != nil {
+			t.Fatalf("HexSetFromCompactProto failed: %v", err)
+		}
+		if !rv.Equals(s) {
+			t.Errorf("compact round trip of %v gave %v", s.ToList(), rv.ToList())
+		}
+	}
+}
+
+func TestCompactProtoLongRun(t *testing.T) {
+	s := NewHexSet()
+	for y := -20; y < 2*(maxCompactRunLength+10); y += 2 {
+		s.AddHex(1, y+1)
+	}
+	s.AddHex(0, 0)
+
+	rv, err := HexSetFromCompactProto(s.ToCompactProto())
+	if err != nil {
+		t.Fatalf("HexSetFromCompactProto failed: %v", err)
+	}
+	if !rv.Equals(s) {
+		t.Errorf("compact round trip of a long column gave %d hexes, expected %d", rv.Size(), s.Size())
+	}
+}
+
+func TestCompactProtoIsCompact(t *testing.T) {
+	s := NewHexSetAround(Origin, 10)
+	p := s.ToCompactProto()
+	if got, limit := len(p.Runs), 3*21; got > limit {
+		t.Errorf("expected at most %d values for a disk of 21 columns, got %d", limit, got)
+	}
+}
+
+func TestCompactProtoValidation(t *testing.T) {
+	bad := []*pb.CompactHexSet{
+		{Runs: []int32{0, 0}},
+		{Runs: []int32{1, 0, 1}},
+		{Runs: []int32{0, 0, 0}},
+		{Runs: []int32{0, 0, 3, 0, -2, 1}},
+		{Runs: []int32{2, 0, 1, -1, 1, 1}},
+		{Runs: []int32{0, 0, math.MaxInt32}},
+		{Runs: []int32{0, 0, maxCompactRunLength + 1}},
+	}
+
+	huge := &pb.CompactHexSet{}
+	for i := 0; i <= maxCompactHexSetSize/maxCompactRunLength; i++ {
+		huge.Runs = append(huge.Runs, 2, 0, maxCompactRunLength)
+	}
+	bad = append(bad, huge)
+
+	for _, p := range bad {
+		if _, err := HexSetFromCompactProto(p); err == nil {
+			t.Errorf("expected error decoding %v", p.Runs)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package hex
+
+import (
+	"fmt"
+)
+
+// SpaceTime is a hex at a particular tick.
+type SpaceTime struct {
+	P HexCoord
+	T int
+}
+
+type spaceTimeEdge struct {
+	from, to HexCoord
+	t        int
+}
+
+// ReservationTable records which hexes agents occupy at which ticks, and
+// the steps they take between ticks, so that other agents can avoid them.
+// An agent that has finished its path keeps occupying its last hex.
+type ReservationTable struct {
+	cells   map[SpaceTime]int
+	edges   map[spaceTimeEdge]int
+	parked  map[HexCoord]int
+	lastUse map[HexCoord]int
+	// base, if set, holds further reservations which are consulted but
+	// never modified through this table.
+	base *ReservationTable
+}
+
+// NewReservationTable creates a new (empty) ReservationTable.
+func NewReservationTable() *ReservationTable {
+	return &ReservationTable{
+		cells:   map[SpaceTime]int{},
+		edges:   map[spaceTimeEdge]int{},
+		parked:  map[HexCoord]int{},
+		lastUse: map[HexCoord]int{},
+	}
+}
+
+// Reserve reserves the hexes of a schedule for an agent, where the agent is
+// at schedule[i] at tick i and stays at its last hex indefinitely.
+func (r *ReservationTable) Reserve(agent int, schedule []HexCoord) {
+	for t, p := range schedule {
+		r.cells[SpaceTime{p, t}] = agent
+		if last, ok := r.lastUse[p]; !ok || t > last {
+			r.lastUse[p] = t
+		}
+		if t > 0 && schedule[t-1] != p {
+			r.edges[spaceTimeEdge{schedule[t-1], p, t - 1}] = agent
+		}
+	}
+	if n := len(schedule); n > 0 {
+		r.parked[schedule[n-1]] = n - 1
+	}
+}
+
+// IsFree checks whether a hex is unoccupied at a tick.
+func (r *ReservationTable) IsFree(p HexCoord, t int) bool {
+	if _, ok := r.cells[SpaceTime{p, t}]; ok {
+		return false
+	}
+	if since, ok := r.parked[p]; ok && t >= since {
+		return false
+	}
+	return r.base == nil || r.base.IsFree(p, t)
+}
+
+// CanMove checks whether an agent may step from one hex at tick t to
+// another (or the same, for waiting) at tick t+1 without colliding with
+// reserved agents, including by swapping places head-on.
+func (r *ReservationTable) CanMove(from, to HexCoord, t int) bool {
+	if !r.IsFree(to, t+1) {
+		return false
+	}
+	for layer := r; layer != nil; layer = layer.base {
+		if _, swap := layer.edges[spaceTimeEdge{to, from, t}]; swap {
+			return false
+		}
+	}
+	return true
+}
+
+// Advance moves the ReservationTable forward in time by a number of ticks,
+// dropping reservations for the ticks that have passed, so that the new
+// current tick becomes tick 0 (the tick at which PlanCooperative starts).
+func (r *ReservationTable) Advance(ticks int) {
+	cells := map[SpaceTime]int{}
+	for k, v := range r.cells {
+		if k.T >= ticks {
+			cells[SpaceTime{k.P, k.T - ticks}] = v
+		}
+	}
+	edges := map[spaceTimeEdge]int{}
+	for k, v := range r.edges {
+		if k.t >= ticks {
+			edges[spaceTimeEdge{k.from, k.to, k.t - ticks}] = v
+		}
+	}
+	for p, since := range r.parked {
+		if since -= ticks; since < 0 {
+			since = 0
+		}
+		r.parked[p] = since
+	}
+	for p, last := range r.lastUse {
+		if last < ticks {
+			delete(r.lastUse, p)
+		} else {
+			r.lastUse[p] = last - ticks
+		}
+	}
+	r.cells, r.edges = cells, edges
+}
+
+// overlay creates an empty ReservationTable on top of this one, in which
+// reservations can be made without modifying this one.
+func (r *ReservationTable) overlay() *ReservationTable {
+	rv := NewReservationTable()
+	rv.base = r
+	return rv
+}
+
+// isFreeFrom checks whether a hex is unoccupied from a tick onwards.
+func (r *ReservationTable) isFreeFrom(p HexCoord, t int) bool {
+	if _, ok := r.parked[p]; ok {
+		return false
+	}
+	if last, ok := r.lastUse[p]; ok && last >= t {
+		return false
+	}
+	return r.base == nil || r.base.isFreeFrom(p, t)
+}
+
+// CooperativeAgent is an agent to be scheduled by PlanCooperative.
+type CooperativeAgent struct {
+	Start HexCoord
+	Goal  HexCoord
+}
+
+// CooperativeParams provides parameters for planning paths for several
+// agents at once. Agents are planned in order, so earlier agents have
+// priority. Cost is as for AStarParams, and each tick spent waiting costs
+// WaitCost. The Heuristic is optional, and estimates the cost between two
+// hexes. Schedules may be at most MaxTicks long. If Reservations is set,
+// agents also avoid the reservations already in it, and their schedules are
+// added to it if every agent is planned successfully; call Advance on it as
+// time passes, so that reservations for past ticks are dropped.
+type CooperativeParams struct {
+	Agents       []CooperativeAgent
+	Cost         func(HexCoord, HexCoord) (float64, bool)
+	WaitCost     float64
+	Heuristic    func(HexCoord, HexCoord) float64
+	MaxTicks     int
+	Reservations *ReservationTable
+}
+
+// CooperativeResult represents conflict-free schedules for a group of
+// agents, where Schedules[i][t] is the hex of agent i at tick t. Each agent
+// stays at the end of its schedule once it is done.
+type CooperativeResult struct {
+	Schedules [][]HexCoord
+	Costs     []float64
+}
+
+// PlanCooperative plans conflict-free schedules for a group of agents over
+// space-time, with a reservation table (cooperative A*). Agents may wait in
+// place, and never occupy the same hex or swap places in the same tick.
+func PlanCooperative(params *CooperativeParams) (*CooperativeResult, error) {
+	if params.MaxTicks <= 0 {
+		return nil, fmt.Errorf("cooperative planning needs a positive MaxTicks")
+	}
+
+	// Agents are planned against an overlay of the reservations, so that
+	// the caller's table is left untouched if any agent fails.
+	reservations := NewReservationTable()
+	if params.Reservations != nil {
+		reservations = params.Reservations.overlay()
+	}
+
+	rv := &CooperativeResult{}
+	for i, agent := range params.Agents {
+		goal := agent.Goal
+
+		var heuristic func(SpaceTime) float64
+		if params.Heuristic != nil {
+			heuristic = func(s SpaceTime) float64 { return params.Heuristic(s.P, goal) }
+		}
+
+		result, err := StateSearch(&StateSearchParams[SpaceTime]{
+			Start: []SpaceTime{{agent.Start, 0}},
+			IsGoal: func(s SpaceTime) bool {
+				return s.P == goal && reservations.isFreeFrom(goal, s.T)
+			},
+			Successors: func(s SpaceTime) []StateStep[SpaceTime] {
+				if s.T >= params.MaxTicks {
+					return nil
+				}
+
+				var rv []StateStep[SpaceTime]
+				if reservations.CanMove(s.P, s.P, s.T) {
+					rv = append(rv, StateStep[SpaceTime]{
+						State:  SpaceTime{s.P, s.T + 1},
+						Cost:   params.WaitCost,
+						Action: -1,
+					})
+				}
+				for _, d := range OrderedDirections {
+					nb := s.P.AddDelta(Directions[d])
+					cost, ok := params.Cost(s.P, nb)
+					if !ok || !reservations.CanMove(s.P, nb, s.T) {
+						continue
+					}
+					rv = append(rv, StateStep[SpaceTime]{
+						State:  SpaceTime{nb, s.T + 1},
+						Cost:   cost,
+						Action: int(d),
+					})
+				}
+				return rv
+			},
+			Heuristic: heuristic,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("no schedule for agent %d: %w", i, err)
+		}
+
+		schedule := make([]HexCoord, len(result.Path))
+		for t, s := range result.Path {
+			schedule[t] = s.P
+		}
+		reservations.Reserve(i, schedule)
+		rv.Schedules = append(rv.Schedules, schedule)
+		rv.Costs = append(rv.Costs, result.Cost)
+	}
+
+	if params.Reservations != nil {
+		for i, schedule := range rv.Schedules {
+			params.Reservations.Reserve(i, schedule)
+		}
+	}
+
+	return rv, nil
+}
@@ -0,0 +1,190 @@
+package hex
+
+import (
+	"testing"
+)
+
+func checkSchedules(t *testing.T, params *CooperativeParams, result *CooperativeResult) {
+	at := func(schedule []HexCoord, tick int) HexCoord {
+		if tick >= len(schedule) {
+			return schedule[len(schedule)-1]
+		}
+		return schedule[tick]
+	}
+
+	ticks := 0
+	for i, s := range result.Schedules {
+		if s[0] != params.Agents[i].Start || s[len(s)-1] != params.Agents[i].Goal {
+			t.Errorf("schedule %d does not go from start to goal: %v", i, s)
+		}
+		for tick := 1; tick < len(s); tick++ {
+			if s[tick] != s[tick-1] && s[tick].StepsTo(s[tick-1]) != 1 {
+				t.Errorf("schedule %d jumps at tick %d: %v", i, tick, s)
+			}
+			if _, ok := params.Cost(s[tick-1], s[tick]); !ok && s[tick] != s[tick-1] {
+				t.Errorf("schedule %d makes an illegal step at tick %d", i, tick)
+			}
+		}
+		if len(s) > ticks {
+			ticks = len(s)
+		}
+	}
+
+	for tick := 0; tick < ticks; tick++ {
+		for i, a := range result.Schedules {
+			for j := i + 1; j < len(result.Schedules); j++ {
+				b := result.Schedules[j]
+				if at(a, tick) == at(b, tick) {
+					t.Errorf("agents %d and %d collide at %v at tick %d", i, j, at(a, tick), tick)
+				}
+				if tick > 0 && at(a, tick) == at(b, tick-1) && at(b, tick) == at(a, tick-1) && at(a, tick) != at(a, tick-1) {
+					t.Errorf("agents %d and %d swap places at tick %d", i, j, tick)
+				}
+			}
+		}
+	}
+}
+
+func TestPlanCooperativeHeadOn(t *testing.T) {
+	region := NewHexSetAround(Origin, 3)
+	params := &CooperativeParams{
+		Agents: []CooperativeAgent{
+			{Start: NewHex(0, -6), Goal: NewHex(0, 6)},
+			{Start: NewHex(0, 6), Goal: NewHex(0, -6)},
+			{Start: NewHex(-3, 3), Goal: NewHex(3, -3)},
+			{Start: NewHex(3, 3), Goal: NewHex(-3, -3)},
+		},
+		Cost:      func(a, b HexCoord) (float64, bool) { return 1, region.Contains(b) },
+		WaitCost:  1,
+		Heuristic: func(a, b HexCoord) float64 { return float64(a.StepsTo(b)) },
+		MaxTicks:  30,
+	}
+
+	result, err := PlanCooperative(params)
+	if err != nil {
+		t.Fatalf("PlanCooperative failed: %v", err)
+	}
+	checkSchedules(t, params, result)
+
+	if result.Costs[0] != 6 {
+		t.Errorf("expected first agent to take the direct path, got cost %v", result.Costs[0])
+	}
+}
+
+func TestPlanCooperativeWaits(t *testing.T) {
+	// A single-file corridor which the second agent must cross, so it has
+	// to wait for the first agent to pass.
+	corridor := NewHexSet()
+	for y := -8; y <= 8; y += 2 {
+		corridor.AddHex(0, y)
+	}
+	corridor.AddHex(-1, 1)
+	corridor.AddHex(1, -1)
+
+	params := &CooperativeParams{
+		Agents: []CooperativeAgent{
+			{Start: NewHex(0, -2), Goal: NewHex(0, 8)},
+			{Start: NewHex(-1, 1), Goal: NewHex(1, -1)},
+		},
+		Cost:     func(a, b HexCoord) (float64, bool) { return 1, corridor.Contains(b) },
+		WaitCost: 1,
+		MaxTicks: 30,
+	}
+
+	result, err := PlanCooperative(params)
+	if err != nil {
+		t.Fatalf("PlanCooperative failed: %v", err)
+	}
+	checkSchedules(t, params, result)
+
+	waited := false
+	s := result.Schedules[1]
+	for tick := 1; tick < len(s); tick++ {
+		if s[tick] == s[tick-1] {
+			waited = true
+		}
+	}
+	if !waited {
+		t.Errorf("expected second agent to wait, got %v", s)
+	}
+}
+
+func TestPlanCooperativeImpossible(t *testing.T) {
+	corridor := NewHexSet()
+	for y := -4; y <= 4; y += 2 {
+		corridor.AddHex(0, y)
+	}
+	_, err := PlanCooperative(&CooperativeParams{
+		Agents: []CooperativeAgent{
+			{Start: NewHex(0, -4), Goal: NewHex(0, 4)},
+			{Start: NewHex(0, 4), Goal: NewHex(0, -4)},
+		},
+		Cost:     func(a, b HexCoord) (float64, bool) { return 1, corridor.Contains(b) },
+		WaitCost: 1,
+		MaxTicks: 20,
+	})
+	if err == nil {
+		t.Errorf("expected agents in a single-file corridor to be unable to pass")
+	}
+}
+
+func TestPlanCooperativeLeavesReservationsOnFailure(t *testing.T) {
+	corridor := NewHexSet()
+	for y := -4; y <= 4; y += 2 {
+		corridor.AddHex(0, y)
+	}
+	reservations := NewReservationTable()
+	_, err := PlanCooperative(&CooperativeParams{
+		Agents: []CooperativeAgent{
+			{Start: NewHex(0, -4), Goal: NewHex(0, 4)},
+			{Start: NewHex(0, 4), Goal: NewHex(0, -4)},
+		},
+		Cost:         func(a, b HexCoord) (float64, bool) { return 1, corridor.Contains(b) },
+		WaitCost:     1,
+		MaxTicks:     20,
+		Reservations: reservations,
+	})
+	if err == nil {
+		t.Fatalf("expected agents in a single-file corridor to be unable to pass")
+	}
+	for _, p := range corridor.ToOrderedList() {
+		for tick := 0; tick < 20; tick++ {
+			if !reservations.IsFree(p, tick) {
+				t.Fatalf("failed planning left a reservation at %v at tick %d", p, tick)
+			}
+		}
+	}
+}
+
+func TestReservationTableTickZero(t *testing.T) {
+	r := NewReservationTable()
+	r.Reserve(0, []HexCoord{Origin, NewHex(0, 2)})
+	if r.isFreeFrom(Origin, 0) {
+		t.Errorf("expected hex occupied at tick 0 not to be free from tick 0")
+	}
+	if !r.isFreeFrom(Origin, 1) {
+		t.Errorf("expected hex left after tick 0 to be free from tick 1")
+	}
+}
+
+func TestReservationTableAdvance(t *testing.T) {
+	a, b, c := NewHex(0, 0), NewHex(0, 2), NewHex(0, 4)
+	r := NewReservationTable()
+	r.Reserve(0, []HexCoord{a, b, c})
+
+	r.Advance(1)
+	if !r.IsFree(a, 0) || r.IsFree(b, 0) || !r.IsFree(c, 0) || r.IsFree(c, 1) {
+		t.Errorf("expected reservations to move one tick earlier")
+	}
+	if r.CanMove(c, b, 0) {
+		t.Errorf("expected swap with the advanced schedule to be forbidden")
+	}
+	if !r.isFreeFrom(a, 0) || r.isFreeFrom(b, 0) || !r.isFreeFrom(b, 1) {
+		t.Errorf("wrong last use after advancing")
+	}
+
+	r.Advance(5)
+	if len(r.cells) != 0 || len(r.edges) != 0 || r.IsFree(c, 0) {
+		t.Errorf("expected only the parked agent to remain, got %v and %v", r.cells, r.edges)
+	}
+}
@@ -0,0 +1,117 @@
+package hex
+
+import (
+	"fmt"
+)
+
+// DistanceFieldResult represents the cheapest cost of reaching each hex from
+// a set of sources, along with the direction from each hex to its
+// predecessor on a cheapest path (so Predecessor has no entries for the
+// sources).
+type DistanceFieldResult struct {
+	Cost        HexMap[float64]
+	Predecessor HexMap[HexDir]
+}
+
+type distanceFieldNode struct {
+	point   HexCoord
+	cost    float64
+	seq     int
+	dir     HexDir
+	hasPred bool
+}
+
+// DistanceField computes the cheapest cost of reaching every hex from the
+// nearest of a set of sources, using Dijkstra's algorithm. Step costs are
+// given by the cost callback as for AStarParams, where cost(a, b) is the cost
+// of stepping from a (nearer the sources) to b. Hexes costing more than
+// maxCost are not included; a maxCost of zero means no limit, in which case
+// the cost callback must make only finitely many hexes reachable.
+// Ties are broken deterministically.
+func DistanceField(sources *HexSet, cost func(HexCoord, HexCoord) (float64, bool), maxCost float64) *DistanceFieldResult {
+	rv := &DistanceFieldResult{
+		Cost:        HexMap[float64]{},
+		Predecessor: HexMap[HexDir]{},
+	}
+
+	q := newPriorityQueue(func(a, b distanceFieldNode) bool {
+		if a.cost != b.cost {
+			return a.cost < b.cost
+		}
+		return a.seq < b.seq
+	})
+	seq := 0
+	push := func(n distanceFieldNode) {
+		n.seq = seq
+		seq++
+		q.Push(n)
+	}
+
+	for _, p := range sources.ToOrderedList() {
+		push(distanceFieldNode{point: p})
+	}
+
+	for q.Len() > 0 {
+		current := q.Pop()
+		if _, done := rv.Cost[current.point]; done {
+			continue
+		}
+
+		rv.Cost[current.point] = current.cost
+		if current.hasPred {
+			rv.Predecessor[current.point] = current.dir
+		}
+
+		for _, d := range OrderedDirections {
+			nb := current.point.AddDelta(Directions[d])
+			if _, done := rv.Cost[nb]; done {
+				continue
+			}
+			stepCost, ok := cost(current.point, nb)
+			if !ok {
+				continue
+			}
+			total := current.cost + stepCost
+			if maxCost != 0 && total > maxCost {
+				continue
+			}
+			push(distanceFieldNode{
+				point:   nb,
+				cost:    total,
+				dir:     d.Opposite(),
+				hasPred: true,
+			})
+		}
+	}
+
+	return rv
+}
+
+// NextStep finds the next hex on a cheapest path from a hex back to the
+// sources. The second return value is false if the hex is a source or was
+// not reached.
+func (r *DistanceFieldResult) NextStep(p HexCoord) (HexCoord, bool) {
+	d, ok := r.Predecessor[p]
+	if !ok {
+		return p, false
+	}
+	return p.AddDelta(Directions[d]), true
+}
+
+// PathToSource computes a cheapest path from a hex back to the sources, as
+// a list starting with the hex and ending with a source.
+func (r *DistanceFieldResult) PathToSource(p HexCoord) ([]HexCoord, error) {
+	if _, ok := r.Cost[p]; !ok {
+		return nil, fmt.Errorf("%v was not reached", p)
+	}
+
+	path := []HexCoord{p}
+	for {
+		next, ok := r.NextStep(p)
+		if !ok {
+			return path, nil
+		}
+		path = append(path, next)
+		p = next
+	}
+}
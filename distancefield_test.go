@@ -0,0 +1,71 @@
+package hex
+
+import (
+	"testing"
+)
+
+func TestDistanceField(t *testing.T) {
+	wall := NewHexSet()
+	for y := -6; y <= 4; y += 2 {
+		wall.AddHex(2, y)
+	}
+	region := NewHexSetAround(Origin, 6)
+	cost := func(a, b HexCoord) (float64, bool) {
+		if wall.Contains(b) || !region.Contains(b) {
+			return 0, false
+		}
+		return 1, true
+	}
+
+	sources := NewHexSet()
+	sources.AddHex(0, 0)
+	sources.AddHex(6, 0)
+
+	field := DistanceField(sources, cost, 0)
+
+	for _, p := range region.Enumerate() {
+		c, ok := field.Cost[p]
+		if wall.Contains(p) {
+			if ok {
+				t.Errorf("wall %v should not be reached", p)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("%v was not reached", p)
+			continue
+		}
+
+		path, err := field.PathToSource(p)
+		if err != nil {
+			t.Fatalf("PathToSource(%v) failed: %v", p, err)
+		}
+		if len(path)-1 != int(c) || !sources.Contains(path[len(path)-1]) {
+			t.Errorf("path %v from %v does not match cost %v", path, p, c)
+		}
+
+		bfsPath, err := BreadthFirstSearchFromMultiple(sources, func(_, b HexCoord) bool {
+			_, ok := cost(Origin, b)
+			return ok
+		}, func(q HexCoord) bool { return q == p })
+		if err != nil || len(bfsPath)-1 != int(c) {
+			t.Errorf("cost %v of %v disagrees with BFS path %v", c, p, bfsPath)
+		}
+	}
+
+	if _, ok := field.NextStep(Origin); ok {
+		t.Errorf("expected no next step from a source")
+	}
+}
+
+func TestDistanceFieldMaxCost(t *testing.T) {
+	cost := func(a, b HexCoord) (float64, bool) { return 1.5, true }
+	field := DistanceField(NewHexSetSingleton(Origin), cost, 4.5)
+
+	if got, expect := len(field.Cost), len(HexDisk(3)); got != expect {
+		t.Errorf("expected %d hexes within cost limit, got %d", expect, got)
+	}
+	if field.Cost[NewHex(0, 6)] != 4.5 {
+		t.Errorf("expected cost 4.5 three steps away, got %v", field.Cost[NewHex(0, 6)])
+	}
+}
@@ -0,0 +1,209 @@
+package hex
+
+import (
+	"math"
+)
+
+// DStarLiteParams provides parameters for a DStarLite planner. Cost is as
+// for AStarParams, and may change between calls to Plan as long as the
+// planner is told with UpdateCosts. Heuristic estimates the cost of the
+// cheapest path between two hexes, and must be consistent. Limits apply to
+// each call to Plan; without a budget, planning towards an unreachable goal
+// on an unbounded grid never ends.
+type DStarLiteParams struct {
+	Start     HexCoord
+	Goal      HexCoord
+	Cost      func(HexCoord, HexCoord) (float64, bool)
+	Heuristic func(HexCoord, HexCoord) float64
+	Limits    SearchLimits
+}
+
+type dStarKey struct {
+	primary, secondary float64
+}
+
+func (k dStarKey) less(o dStarKey) bool {
+	if k.primary != o.primary {
+		return k.primary < o.primary
+	}
+	return k.secondary < o.secondary
+}
+
+type dStarNode struct {
+	point HexCoord
+	g     float64
+	rhs   float64
+	key   dStarKey
+	index int
+}
+
+// DStarLite is an incremental planner, which keeps its search state between
+// calls so that the path to the goal can be repaired cheaply as the agent
+// moves and step costs change. It searches backwards from the goal, using
+// the D* Lite algorithm of Koenig and Likhachev.
+type DStarLite struct {
+	params *DStarLiteParams
+	start  HexCoord
+	last   HexCoord
+	// km accumulates heuristic distances moved, so that queued keys
+	// remain valid lower bounds as the start changes.
+	km    float64
+	nodes map[HexCoord]*dStarNode
+	open  *priorityQueue[*dStarNode]
+}
+
+// NewDStarLite creates a new DStarLite planner.
+func NewDStarLite(params *DStarLiteParams) *DStarLite {
+	d := &DStarLite{
+		params: params,
+		start:  params.Start,
+		last:   params.Start,
+		nodes:  map[HexCoord]*dStarNode{},
+	}
+	d.open = newIndexedPriorityQueue(func(a, b *dStarNode) bool {
+		return a.key.less(b.key)
+	}, func(n *dStarNode, i int) { n.index = i })
+
+	goal := d.node(params.Goal)
+	goal.rhs = 0
+	goal.key = d.calculateKey(goal)
+	d.open.Push(goal)
+
+	return d
+}
+
+func (d *DStarLite) node(p HexCoord) *dStarNode {
+	n, ok := d.nodes[p]
+	if !ok {
+		n = &dStarNode{point: p, g: math.Inf(1), rhs: math.Inf(1), index: -1}
+		d.nodes[p] = n
+	}
+	return n
+}
+
+func (d *DStarLite) g(p HexCoord) float64 {
+	if n, ok := d.nodes[p]; ok {
+		return n.g
+	}
+	return math.Inf(1)
+}
+
+func (d *DStarLite) calculateKey(n *dStarNode) dStarKey {
+	m := math.Min(n.g, n.rhs)
+	return dStarKey{m + d.params.Heuristic(d.start, n.point) + d.km, m}
+}
+
+func (d *DStarLite) updateVertex(p HexCoord) {
+	n := d.node(p)
+	if p != d.params.Goal {
+		n.rhs = math.Inf(1)
+		for _, nb := range p.Neighbours() {
+			if c, ok := d.params.Cost(p, nb); ok {
+				n.rhs = math.Min(n.rhs, c+d.g(nb))
+			}
+		}
+	}
+
+	if n.index >= 0 {
+		d.open.Remove(n.index)
+	}
+	if n.g != n.rhs {
+		n.key = d.calculateKey(n)
+		d.open.Push(n)
+	}
+}
+
+func (d *DStarLite) computeShortestPath() error {
+	monitor := newSearchMonitor(d.params.Limits, nil)
+	defer monitor.finish()
+
+	start := d.node(d.start)
+	for d.open.Len() > 0 && (d.open.Peek().key.less(d.calculateKey(start)) || start.rhs != start.g) {
+		if err := monitor.expand(d.open.Len()); err != nil {
+			return err
+		}
+
+		u := d.open.Peek()
+		oldKey := u.key
+		newKey := d.calculateKey(u)
+
+		switch {
+		case oldKey.less(newKey):
+			u.key = newKey
+			d.open.Fix(u.index)
+		case u.g > u.rhs:
+			u.g = u.rhs
+			d.open.Pop()
+			for _, nb := range u.point.Neighbours() {
+				if _, ok := d.params.Cost(nb, u.point); ok {
+					d.updateVertex(nb)
+				}
+			}
+		default:
+			u.g = math.Inf(1)
+			for _, nb := range u.point.Neighbours() {
+				if _, ok := d.params.Cost(nb, u.point); ok {
+					d.updateVertex(nb)
+				}
+			}
+			d.updateVertex(u.point)
+		}
+	}
+
+	return nil
+}
+
+// MoveTo tells the planner that the agent has moved to a new hex, which is
+// the start of subsequently planned paths.
+func (d *DStarLite) MoveTo(p HexCoord) {
+	d.start = p
+	d.km += d.params.Heuristic(d.last, d.start)
+	d.last = d.start
+}
+
+// UpdateCosts tells the planner that the costs of stepping into or out of a
+// set of hexes have changed.
+func (d *DStarLite) UpdateCosts(changed *HexSet) {
+	affected := NewHexSet()
+	for _, p := range changed.ToOrderedList() {
+		affected.Add(p)
+		for _, nb := range p.Neighbours() {
+			affected.Add(nb)
+		}
+	}
+	for _, p := range affected.ToOrderedList() {
+		d.updateVertex(p)
+	}
+}
+
+// Plan computes a cheapest path from the agent's current hex to the goal,
+// reusing as much work from previous calls as possible. Errors are ErrNoPath,
+// ErrSearchBudget or the error of the context in Limits.
+func (d *DStarLite) Plan() (*AStarResult, error) {
+	if err := d.computeShortestPath(); err != nil {
+		return nil, err
+	}
+
+	cost := d.g(d.start)
+	if math.IsInf(cost, 1) {
+		return nil, ErrNoPath
+	}
+
+	rv := &AStarResult{Cost: cost, Path: []HexCoord{d.start}}
+	for p := d.start; p != d.params.Goal; {
+		best, bestCost := p, math.Inf(1)
+		for _, nb := range p.Neighbours() {
+			c, ok := d.params.Cost(p, nb)
+			if ok && c+d.g(nb) < bestCost {
+				best, bestCost = nb, c+d.g(nb)
+			}
+		}
+		if math.IsInf(bestCost, 1) || len(rv.Path) > len(d.nodes) {
+			return nil, ErrNoPath
+		}
+		p = best
+		rv.Path = append(rv.Path, p)
+	}
+
+	return rv, nil
+}
@@ -0,0 +1,89 @@
+package hex
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestDStarLiteReplanning(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	region := NewHexSetAround(Origin, 10)
+	start, goal := NewHex(-8, 0), NewHex(8, 0)
+	blocked := NewHexSet()
+	for _, p := range region.ToOrderedList() {
+		if p != start && p != goal && r.Float64() < 0.2 {
+			blocked.Add(p)
+		}
+	}
+	cost := func(a, b HexCoord) (float64, bool) {
+		return 1, region.Contains(b) && !blocked.Contains(b)
+	}
+	heuristic := func(a, b HexCoord) float64 { return float64(a.StepsTo(b)) }
+
+	planner := NewDStarLite(&DStarLiteParams{
+		Start:     start,
+		Goal:      goal,
+		Cost:      cost,
+		Heuristic: heuristic,
+	})
+
+	position := start
+	for steps := 0; position != goal; steps++ {
+		if steps > 100 {
+			t.Fatalf("agent did not reach goal")
+		}
+
+		path, err := planner.Plan()
+		expect, expectErr := AStar(&AStarParams{
+			Start:     NewHexSetSingleton(position),
+			IsGoal:    func(p HexCoord) bool { return p == goal },
+			Cost:      cost,
+			Heuristic: func(p HexCoord) float64 { return heuristic(p, goal) },
+		})
+		if expectErr != nil {
+			if !errors.Is(err, ErrNoPath) {
+				t.Fatalf("expected no path from %v, got %v", position, err)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatalf("Plan from %v failed: %v", position, err)
+		}
+		if path.Cost != expect.Cost || len(path.Path) != len(expect.Path) || path.Path[0] != position {
+			t.Fatalf("expected path of cost %v from %v, got %v", expect.Cost, position, path)
+		}
+
+		// Walls appear and disappear near the agent as it moves.
+		changed := NewHexSet()
+		for i := 0; i < 3; i++ {
+			p := position.AddDelta(NewHex(2*r.Intn(3), 2*r.Intn(3)-2))
+			if p == goal || p == path.Path[1] {
+				continue
+			}
+			if blocked.Contains(p) {
+				blocked.Remove(p)
+			} else {
+				blocked.Add(p)
+			}
+			changed.Add(p)
+		}
+
+		position = path.Path[1]
+		planner.MoveTo(position)
+		planner.UpdateCosts(changed)
+	}
+}
+
+func TestDStarLiteUnreachable(t *testing.T) {
+	planner := NewDStarLite(&DStarLiteParams{
+		Start:     Origin,
+		Goal:      NewHex(0, 10),
+		Cost:      func(a, b HexCoord) (float64, bool) { return 1, b.Radius() != 3 },
+		Heuristic: func(a, b HexCoord) float64 { return float64(a.StepsTo(b)) },
+		Limits:    SearchLimits{MaxExpansions: 10000},
+	})
+	if _, err := planner.Plan(); !errors.Is(err, ErrNoPath) && !errors.Is(err, ErrSearchBudget) {
+		t.Errorf("expected failure, got %v", err)
+	}
+}
@@ -0,0 +1,332 @@
+package hex
+
+import (
+	"context"
+	"fmt"
+)
+
+// TilingPiece describes a piece that may be placed when tiling a region.
+type TilingPiece struct {
+	Shape   *HexSet
+	Rotate  bool
+	Reflect bool
+}
+
+// TilingParams provides parameters for a tiling search.
+// Unless ReusePieces is set, every piece must be placed exactly once;
+// otherwise each piece may be placed any number of times.
+type TilingParams struct {
+	Region      *HexSet
+	Pieces      []TilingPiece
+	ReusePieces bool
+}
+
+// TilingPlacement describes the placement of one piece in a tiling. The
+// placed cells are the piece's shape, mirrored (if Mirrored is set), then
+// rotated counterclockwise by Rotation steps, then translated by Offset.
+type TilingPlacement struct {
+	Piece    int
+	Rotation int
+	Mirrored bool
+	Offset   HexCoord
+	Cells    *HexSet
+}
+
+// Tiling is a solution to a tiling search: a set of piece placements that
+// together cover the region exactly.
+type Tiling struct {
+	Placements []TilingPlacement
+}
+
+// tilingCheckInterval is the number of search steps between checks for
+// cancellation.
+const tilingCheckInterval = 1024
+
+// dlxMatrix is a sparse 0/1 matrix represented with dancing links, as in
+// Knuth's Algorithm X. Index 0 is the root and indices 1..n are the column
+// headers; the remaining indices are the nodes of the rows.
+type dlxMatrix struct {
+	left, right, up, down []int
+	col, row              []int
+	size                  []int
+}
+
+func newDLXMatrix(numCols int) *dlxMatrix {
+	m := &dlxMatrix{size: make([]int, numCols+1)}
+	for i := 0; i <= numCols; i++ {
+		m.left = append(m.left, i-1)
+		m.right = append(m.right, i+1)
+		m.up = append(m.up, i)
+		m.down = append(m.down, i)
+		m.col = append(m.col, i)
+		m.row = append(m.row, -1)
+	}
+	m.left[0] = numCols
+	m.right[numCols] = 0
+	return m
+}
+
+// addRow adds a row with ones in the given (1-based) columns.
+func (m *dlxMatrix) addRow(row int, cols []int) {
+	first := -1
+	for _, c := range cols {
+		n := len(m.col)
+		m.col = append(m.col, c)
+		m.row = append(m.row, row)
+		m.up = append(m.up, m.up[c])
+		m.down = append(m.down, c)
+		m.down[m.up[c]] = n
+		m.up[c] = n
+		m.size[c]++
+
+		if first < 0 {
+			first = n
+			m.left = append(m.left, n)
+			m.right = append(m.right, n)
+		} else {
+			m.left = append(m.left, m.left[first])
+			m.right = append(m.right, first)
+			m.right[m.left[first]] = n
+			m.left[first] = n
+		}
+	}
+}
+
+func (m *dlxMatrix) cover(c int) {
+	m.right[m.left[c]] = m.right[c]
+	m.left[m.right[c]] = m.left[c]
+	for i := m.down[c]; i != c; i = m.down[i] {
+		for j := m.right[i]; j != i; j = m.right[j] {
+			m.down[m.up[j]] = m.down[j]
+			m.up[m.down[j]] = m.up[j]
+			m.size[m.col[j]]--
+		}
+	}
+}
+
+func (m *dlxMatrix) uncover(c int) {
+	for i := m.up[c]; i != c; i = m.up[i] {
+		for j := m.left[i]; j != i; j = m.left[j] {
+			m.size[m.col[j]]++
+			m.down[m.up[j]] = j
+			m.up[m.down[j]] = j
+		}
+	}
+	m.right[m.left[c]] = c
+	m.left[m.right[c]] = c
+}
+
+// dlxSearch runs Algorithm X on a dlxMatrix, calling onSolution with the
+// rows of each exact cover found. The search stops early if onSolution
+// returns false or the context is done.
+type dlxSearch struct {
+	m          *dlxMatrix
+	ctx        context.Context
+	onSolution func([]int) bool
+	partial    []int
+	steps      int
+	err        error
+}
+
+func (s *dlxSearch) run() bool {
+	m := s.m
+
+	if m.right[0] == 0 {
+		return s.onSolution(s.partial)
+	}
+
+	s.steps++
+	if s.steps%tilingCheckInterval == 0 {
+		if err := s.ctx.Err(); err != nil {
+			s.err = err
+			return false
+		}
+	}
+
+	c := m.right[0]
+	for j := m.right[c]; j != 0; j = m.right[j] {
+		if m.size[j] < m.size[c] {
+			c = j
+		}
+	}
+	if m.size[c] == 0 {
+		return true
+	}
+
+	m.cover(c)
+	defer m.uncover(c)
+
+	for r := m.down[c]; r != c; r = m.down[r] {
+		s.partial = append(s.partial, m.row[r])
+		for j := m.right[r]; j != r; j = m.right[j] {
+			m.cover(m.col[j])
+		}
+
+		keepGoing := s.run()
+
+		for j := m.left[r]; j != r; j = m.left[j] {
+			m.uncover(m.col[j])
+		}
+		s.partial = s.partial[:len(s.partial)-1]
+
+		if !keepGoing {
+			return false
+		}
+	}
+
+	return true
+}
+
+// pieceOrientations computes the distinct orientations a piece may be
+// placed in. Each orientation is normalized so that its first element in
+// ToOrderedList order is the origin.
+func pieceOrientations(index int, piece TilingPiece) []TilingPlacement {
+	var rv []TilingPlacement
+	var seen []*HexSet
+
+	reflections := []bool{false}
+	if piece.Reflect {
+		reflections = append(reflections, true)
+	}
+	rotations := 1
+	if piece.Rotate {
+		rotations = 6
+	}
+
+	for _, mirrored := range reflections {
+		shape := piece.Shape
+		if mirrored {
+			shape = shape.Mirrored()
+		}
+		for rot := 0; rot < rotations; rot++ {
+			oriented := shape.Rotated(rot)
+			first := oriented.ToOrderedList()[0]
+			normalized := oriented.Translated(first.Negation())
+
+			duplicate := false
+			for _, other := range seen {
+				if other.Equals(normalized) {
+					duplicate = true
+					break
+				}
+			}
+			if duplicate {
+				continue
+			}
+			seen = append(seen, normalized)
+
+			rv = append(rv, TilingPlacement{
+				Piece:    index,
+				Rotation: rot,
+				Mirrored: mirrored,
+				Offset:   first.Negation(),
+				Cells:    normalized,
+			})
+		}
+	}
+
+	return rv
+}
+
+// SolveTiling searches for exact tilings of a region, calling onSolution for
+// each one found. Returning false from onSolution stops the search. The number
+// of solutions found is returned; if the context is cancelled, the count so
+// far is returned along with the context's error.
+//
+// Pieces with identical shapes are treated as distinct, so swapping them
+// yields distinct solutions.
+func SolveTiling(ctx context.Context, params *TilingParams, onSolution func(*Tiling) bool) (int, error) {
+	cells := params.Region.ToOrderedList()
+	cellColumn := map[HexCoord]int{}
+	for i, p := range cells {
+		cellColumn[p] = i + 1
+	}
+
+	numCols := len(cells)
+	if !params.ReusePieces {
+		numCols += len(params.Pieces)
+	}
+
+	m := newDLXMatrix(numCols)
+	var rows []TilingPlacement
+
+	for i, piece := range params.Pieces {
+		if piece.Shape.Size() == 0 {
+			return 0, fmt.Errorf("tiling piece %d is empty", i)
+		}
+
+		for _, orientation := range pieceOrientations(i, piece) {
+			for _, anchor := range cells {
+				cols := []int{}
+				if !params.ReusePieces {
+					cols = append(cols, len(cells)+i+1)
+				}
+
+				fits := true
+				for _, p := range orientation.Cells.Enumerate() {
+					col, ok := cellColumn[p.AddDelta(anchor)]
+					if !ok {
+						fits = false
+						break
+					}
+					cols = append(cols, col)
+				}
+				if !fits {
+					continue
+				}
+
+				placement := orientation
+				placement.Offset = orientation.Offset.AddDelta(anchor)
+				placement.Cells = orientation.Cells.Translated(anchor)
+				m.addRow(len(rows), cols)
+				rows = append(rows, placement)
+			}
+		}
+	}
+
+	count := 0
+	search := &dlxSearch{
+		m:   m,
+		ctx: ctx,
+		onSolution: func(rowIndices []int) bool {
+			count++
+			if onSolution == nil {
+				return true
+			}
+			tiling := &Tiling{}
+			for _, r := range rowIndices {
+				tiling.Placements = append(tiling.Placements, rows[r])
+			}
+			return onSolution(tiling)
+		},
+	}
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	search.run()
+
+	return count, search.err
+}
+
+// FindTiling finds a single exact tiling of a region. An error is returned
+// if no tiling exists.
+func FindTiling(ctx context.Context, params *TilingParams) (*Tiling, error) {
+	var rv *Tiling
+	_, err := SolveTiling(ctx, params, func(t *Tiling) bool {
+		rv = t
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rv == nil {
+		return nil, fmt.Errorf("no tiling found")
+	}
+	return rv, nil
+}
+
+// CountTilings counts the exact tilings of a region.
+func CountTilings(ctx context.Context, params *TilingParams) (int, error) {
+	return SolveTiling(ctx, params, nil)
+}
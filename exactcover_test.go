@@ -0,0 +1,123 @@
+package hex
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHexCoordRotation(t *testing.T) {
+	for i, d := range OrderedDirections {
+		next := OrderedDirections[(i+1)%6]
+		if got := Directions[d].Rotated(1); got != Directions[next] {
+			t.Errorf("expected %v rotated once to be %v, got %v", d, next, got)
+		}
+	}
+
+	p := NewHex(3, 7)
+	if got := p.Rotated(6); got != p {
+		t.Errorf("expected full rotation of %v to be identity, got %v", p, got)
+	}
+	if got := p.Rotated(-1).Rotated(1); got != p {
+		t.Errorf("expected clockwise rotation to undo counterclockwise, got %v", got)
+	}
+}
+
+func TestCountTilingsOfRing(t *testing.T) {
+	domino := NewHexSet()
+	domino.AddHex(0, 0)
+	domino.AddHex(0, 2)
+
+	region := NewHexSet()
+	for _, p := range HexCircle(1) {
+		region.Add(p)
+	}
+
+	n, err := CountTilings(context.Background(), &TilingParams{
+		Region:      region,
+		Pieces:      []TilingPiece{{Shape: domino, Rotate: true}},
+		ReusePieces: true,
+	})
+	if err != nil {
+		t.Fatalf("CountTilings failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 domino tilings of a six-cell ring, got %d", n)
+	}
+
+	n, err = CountTilings(context.Background(), &TilingParams{
+		Region:      region,
+		Pieces:      []TilingPiece{{Shape: domino}},
+		ReusePieces: true,
+	})
+	if err != nil {
+		t.Fatalf("CountTilings failed: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("expected no tilings with an unrotatable vertical domino, got %d", n)
+	}
+}
+
+func TestFindTilingUsesEachPieceOnce(t *testing.T) {
+	bar := NewHexSet()
+	bar.AddHex(0, 0)
+	bar.AddHex(0, 2)
+	bar.AddHex(0, 4)
+
+	region := NewHexSet()
+	for y := 0; y < 14; y += 2 {
+		region.AddHex(0, y)
+	}
+
+	tiling, err := FindTiling(context.Background(), &TilingParams{
+		Region: region,
+		Pieces: []TilingPiece{
+			{Shape: bar, Rotate: true},
+			{Shape: bar, Rotate: true},
+			{Shape: NewHexSetSingleton(Origin)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("FindTiling failed: %v", err)
+	}
+
+	covered := NewHexSet()
+	used := map[int]bool{}
+	for _, pl := range tiling.Placements {
+		if covered.Intersects(pl.Cells) {
+			t.Errorf("placement %v overlaps previous placements", pl.Cells.ToList())
+		}
+		covered.InPlaceUnion(pl.Cells)
+		used[pl.Piece] = true
+
+		expect := bar
+		if pl.Piece == 2 {
+			expect = NewHexSetSingleton(Origin)
+		}
+		if pl.Mirrored {
+			expect = expect.Mirrored()
+		}
+		if !expect.Rotated(pl.Rotation).Translated(pl.Offset).Equals(pl.Cells) {
+			t.Errorf("placement %+v does not match its orientation and offset", pl)
+		}
+	}
+	if !covered.Equals(region) {
+		t.Errorf("tiling covers %v, expected %v", covered.ToList(), region.ToList())
+	}
+	if len(used) != 3 {
+		t.Errorf("expected all three pieces to be used, used %v", used)
+	}
+}
+
+func TestSolveTilingCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := SolveTiling(ctx, &TilingParams{
+		Region:      NewHexSetAround(Origin, 3),
+		Pieces:      []TilingPiece{{Shape: NewHexSetSingleton(Origin)}},
+		ReusePieces: true,
+	}, nil)
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package hex
+
+import (
+	"fmt"
+)
+
+// FacingState is a position on the hex grid along with the direction a unit
+// there is facing.
+type FacingState struct {
+	Position HexCoord
+	Facing   HexDir
+}
+
+// FacingActionKind is the kind of a FacingAction.
+type FacingActionKind int
+
+const (
+	// MoveForward moves one hex in the facing direction.
+	MoveForward FacingActionKind = iota
+	// MoveBackward moves one hex opposite to the facing direction, without
+	// turning.
+	MoveBackward
+	// TurnLeft turns 60 degrees counterclockwise.
+	TurnLeft
+	// TurnRight turns 60 degrees clockwise.
+	TurnRight
+)
+
+func (k FacingActionKind) String() string {
+	switch k {
+	case MoveForward:
+		return "forward"
+	case MoveBackward:
+		return "backward"
+	case TurnLeft:
+		return "left"
+	case TurnRight:
+		return "right"
+	}
+	return fmt.Sprintf("FacingActionKind(%d)", int(k))
+}
+
+// FacingAction is a single move or turn, along with the resulting state.
+type FacingAction struct {
+	Kind  FacingActionKind
+	State FacingState
+}
+
+// FacingPathParams provides parameters for finding a path for a unit that
+// must turn to face the direction it moves in. Cost gives the cost of
+// moving from one hex to another, as for AStarParams, and each 60-degree turn
+// costs TurnCost. If AllowReverse is set, the unit may also move backwards,
+// at an extra cost of ReverseCost per move. If GoalFacing is set, the unit
+// must end up facing that direction. The Heuristic is optional, and
+// estimates the cost of moving from a hex to the goal.
+type FacingPathParams struct {
+	Start        FacingState
+	Goal         HexCoord
+	GoalFacing   *HexDir
+	Cost         func(HexCoord, HexCoord) (float64, bool)
+	TurnCost     float64
+	AllowReverse bool
+	ReverseCost  float64
+	Heuristic    func(HexCoord) float64
+	MaxCost      float64
+	Limits       SearchLimits
+}
+
+// FacingPath represents the result of a facing-aware path search: the
+// sequence of actions taken from the start, and their total cost.
+type FacingPath struct {
+	Actions []FacingAction
+	Cost    float64
+}
+
+// FindFacingPath finds a cheapest sequence of moves and turns taking a unit
+// from its start state to the goal. Errors are as for AStar.
+func FindFacingPath(params *FacingPathParams) (*FacingPath, error) {
+	var heuristic func(FacingState) float64
+	if params.Heuristic != nil {
+		heuristic = func(s FacingState) float64 { return params.Heuristic(s.Position) }
+	}
+
+	successors := func(s FacingState) []StateStep[FacingState] {
+		rv := []StateStep[FacingState]{
+			{
+				State:  FacingState{s.Position, s.Facing.Rotated(1)},
+				Cost:   params.TurnCost,
+				Action: int(TurnLeft),
+			},
+			{
+				State:  FacingState{s.Position, s.Facing.Rotated(-1)},
+				Cost:   params.TurnCost,
+				Action: int(TurnRight),
+			},
+		}
+
+		ahead := s.Position.AddDelta(Directions[s.Facing])
+		if cost, ok := params.Cost(s.Position, ahead); ok {
+			rv = append(rv, StateStep[FacingState]{
+				State:  FacingState{ahead, s.Facing},
+				Cost:   cost,
+				Action: int(MoveForward),
+			})
+		}
+
+		if params.AllowReverse {
+			behind := s.Position.AddDelta(Directions[s.Facing.Opposite()])
+			if cost, ok := params.Cost(s.Position, behind); ok {
+				rv = append(rv, StateStep[FacingState]{
+					State:  FacingState{behind, s.Facing},
+					Cost:   cost + params.ReverseCost,
+					Action: int(MoveBackward),
+				})
+			}
+		}
+
+		return rv
+	}
+
+	result, err := StateSearch(&StateSearchParams[FacingState]{
+		Start: []FacingState{params.Start},
+		IsGoal: func(s FacingState) bool {
+			return s.Position == params.Goal && (params.GoalFacing == nil || s.Facing == *params.GoalFacing)
+		},
+		Successors: successors,
+		Heuristic:  heuristic,
+		MaxCost:    params.MaxCost,
+		Limits:     params.Limits,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rv := &FacingPath{Cost: result.Cost}
+	for i := 1; i < len(result.Path); i++ {
+		prev, next := result.Path[i-1], result.Path[i]
+		action := FacingAction{State: next}
+		switch {
+		case next.Position == prev.Position && next.Facing == prev.Facing.Rotated(1):
+			action.Kind = TurnLeft
+		case next.Position == prev.Position:
+			action.Kind = TurnRight
+		case next.Position == prev.Position.AddDelta(Directions[prev.Facing]):
+			action.Kind = MoveForward
+		default:
+			action.Kind = MoveBackward
+		}
+		rv.Actions = append(rv.Actions, action)
+	}
+
+	return rv, nil
+}
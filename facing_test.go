@@ -0,0 +1,93 @@
+package hex
+
+import (
+	"testing"
+)
+
+func replayFacingPath(start FacingState, actions []FacingAction) (FacingState, map[FacingActionKind]int, bool) {
+	counts := map[FacingActionKind]int{}
+	s := start
+	for _, a := range actions {
+		switch a.Kind {
+		case MoveForward:
+			s.Position = s.Position.AddDelta(Directions[s.Facing])
+		case MoveBackward:
+			s.Position = s.Position.AddDelta(Directions[s.Facing.Opposite()])
+		case TurnLeft:
+			s.Facing = s.Facing.Rotated(1)
+		case TurnRight:
+			s.Facing = s.Facing.Rotated(-1)
+		}
+		if s != a.State {
+			return s, counts, false
+		}
+		counts[a.Kind]++
+	}
+	return s, counts, true
+}
+
+func TestFindFacingPath(t *testing.T) {
+	unit := func(a, b HexCoord) (float64, bool) { return 1, true }
+	start := FacingState{Origin, North}
+	south := HexDir(South)
+
+	testcases := []struct {
+		params *FacingPathParams
+		cost   float64
+		counts map[FacingActionKind]int
+	}{
+		{
+			params: &FacingPathParams{Goal: NewHex(0, -4), TurnCost: 1},
+			cost:   5,
+			counts: map[FacingActionKind]int{MoveForward: 2, TurnLeft: 3},
+		},
+		{
+			params: &FacingPathParams{Goal: NewHex(0, -4), TurnCost: 1, AllowReverse: true, ReverseCost: 0.5},
+			cost:   3,
+			counts: map[FacingActionKind]int{MoveBackward: 2},
+		},
+		{
+			params: &FacingPathParams{Goal: NewHex(0, 4), GoalFacing: &south, TurnCost: 2},
+			cost:   8,
+			counts: map[FacingActionKind]int{MoveForward: 2, TurnLeft: 3},
+		},
+		{
+			params: &FacingPathParams{Goal: NewHex(2, 2), TurnCost: 1},
+			cost:   3,
+			counts: map[FacingActionKind]int{MoveForward: 2, TurnRight: 1},
+		},
+	}
+
+	for i, tc := range testcases {
+		tc.params.Start = start
+		tc.params.Cost = unit
+		tc.params.Heuristic = func(p HexCoord) float64 { return float64(p.StepsTo(tc.params.Goal)) }
+
+		path, err := FindFacingPath(tc.params)
+		if err != nil {
+			t.Errorf("case %d: FindFacingPath failed: %v", i, err)
+			continue
+		}
+		if path.Cost != tc.cost {
+			t.Errorf("case %d: expected cost %v, got %v: %v", i, tc.cost, path.Cost, path.Actions)
+		}
+
+		end, counts, ok := replayFacingPath(start, path.Actions)
+		if !ok {
+			t.Errorf("case %d: actions %v have inconsistent states", i, path.Actions)
+		}
+		if end.Position != tc.params.Goal || (tc.params.GoalFacing != nil && end.Facing != *tc.params.GoalFacing) {
+			t.Errorf("case %d: actions %v end at %v", i, path.Actions, end)
+		}
+		for _, kind := range []FacingActionKind{MoveForward, MoveBackward, TurnLeft, TurnRight} {
+			// Turning left three times is as good as turning right three times.
+			got, expect := counts[kind], tc.counts[kind]
+			if kind == TurnLeft || kind == TurnRight {
+				got, expect = counts[TurnLeft]+counts[TurnRight], tc.counts[TurnLeft]+tc.counts[TurnRight]
+			}
+			if got != expect {
+				t.Errorf("case %d: expected %d %v actions, got %v", i, expect, kind, path.Actions)
+			}
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package hex
+
+import (
+	"fmt"
+
+	pb "github.com/steinarvk/above-hex/hexpb"
+)
+
+// FlowFieldParams provides parameters for computing a FlowField. Cost gives
+// the cost of a unit stepping from one hex to another, as for AStarParams.
+// Hexes are included only if they are in Region (if set) and cost no more
+// than MaxCost (if nonzero) to reach the goals; at least one of these should
+// bound the field.
+type FlowFieldParams struct {
+	Goals   *HexSet
+	Region  *HexSet
+	Cost    func(HexCoord, HexCoord) (float64, bool)
+	MaxCost float64
+}
+
+// FlowField represents, for every hex from which a goal can be reached, the
+// direction of the first step on a cheapest path to the nearest goal and the
+// cost of that path. Goals have a cost of zero and no direction.
+type FlowField struct {
+	Goals     *HexSet
+	Cost      HexMap[float64]
+	Direction HexMap[HexDir]
+
+	params *FlowFieldParams
+}
+
+type flowFieldNode struct {
+	point HexCoord
+	cost  float64
+	seq   int
+}
+
+// NewFlowField computes a FlowField leading toward a set of goals.
+func NewFlowField(params *FlowFieldParams) (*FlowField, error) {
+	if params.Goals == nil || params.Goals.Size() == 0 {
+		return nil, fmt.Errorf("flow field needs at least one goal")
+	}
+	if params.Region == nil && params.MaxCost == 0 {
+		return nil, fmt.Errorf("flow field needs a region or cost limit")
+	}
+
+	f := &FlowField{
+		Goals:     params.Goals.Clone(),
+		Cost:      HexMap[float64]{},
+		Direction: HexMap[HexDir]{},
+		params:    params,
+	}
+	f.propagate(f.Goals.ToOrderedList())
+
+	return f, nil
+}
+
+func (f *FlowField) contains(p HexCoord) bool {
+	return f.params.Region == nil || f.params.Region.Contains(p)
+}
+
+// propagate runs Dijkstra's algorithm backwards from a set of hexes whose
+// costs are already known, improving the costs of any hexes that can reach
+// them more cheaply.
+func (f *FlowField) propagate(frontier []HexCoord) {
+	q := newPriorityQueue(func(a, b flowFieldNode) bool {
+		if a.cost != b.cost {
+			return a.cost < b.cost
+		}
+		return a.seq < b.seq
+	})
+	seq := 0
+	push := func(p HexCoord, cost float64) {
+		q.Push(flowFieldNode{point: p, cost: cost, seq: seq})
+		seq++
+	}
+
+	for _, p := range frontier {
+		if f.Goals.Contains(p) {
+			f.Cost[p] = 0
+			delete(f.Direction, p)
+		}
+		push(p, f.Cost[p])
+	}
+
+	for q.Len() > 0 {
+		current := q.Pop()
+		if current.cost > f.Cost[current.point] {
+			continue
+		}
+
+		for _, d := range OrderedDirections {
+			nb := current.point.AddDelta(Directions[d])
+			if f.Goals.Contains(nb) || !f.contains(nb) {
+				continue
+			}
+			stepCost, ok := f.params.Cost(nb, current.point)
+			if !ok {
+				continue
+			}
+			total := current.cost + stepCost
+			if f.params.MaxCost != 0 && total > f.params.MaxCost {
+				continue
+			}
+			if old, ok := f.Cost[nb]; ok && old <= total {
+				continue
+			}
+			f.Cost[nb] = total
+			f.Direction[nb] = d.Opposite()
+			push(nb, total)
+		}
+	}
+}
+
+// Next finds the hex to step to from a hex to approach the goals. The second
+// return value is false if the hex is a goal or cannot reach any goal.
+func (f *FlowField) Next(p HexCoord) (HexCoord, bool) {
+	d, ok := f.Direction[p]
+	if !ok {
+		return p, false
+	}
+	return p.AddDelta(Directions[d]), true
+}
+
+// Update recomputes the FlowField after the costs of stepping into or out of
+// a set of hexes have changed. Only the hexes whose cheapest paths could be
+// affected are recomputed.
+func (f *FlowField) Update(changed *HexSet) error {
+	if f.params == nil {
+		return fmt.Errorf("flow field has no parameters to update from")
+	}
+
+	// Every hex whose cheapest path passes through a changed hex must be
+	// recomputed from scratch. A changed goal keeps its cost of zero, but
+	// the hexes stepping into it are invalidated all the same.
+	invalid := NewHexSet()
+	var mark func(p HexCoord)
+	mark = func(p HexCoord) {
+		if invalid.Contains(p) {
+			return
+		}
+		if !f.Goals.Contains(p) {
+			invalid.Add(p)
+		}
+		for _, d := range OrderedDirections {
+			nb := p.AddDelta(Directions[d])
+			if nd, ok := f.Direction[nb]; ok && nd == d.Opposite() {
+				mark(nb)
+			}
+		}
+	}
+	for _, p := range changed.ToOrderedList() {
+		if _, ok := f.Cost[p]; ok {
+			mark(p)
+		}
+	}
+
+	for _, p := range invalid.Enumerate() {
+		delete(f.Cost, p)
+		delete(f.Direction, p)
+	}
+
+	// Any remaining hex next to a changed or invalidated hex may now offer
+	// a cheaper path, so propagation restarts from all of them.
+	touched := invalid.Union(changed)
+	frontier := NewHexSet()
+	for _, p := range touched.Enumerate() {
+		for _, q := range append(p.Neighbours(), p) {
+			if _, ok := f.Cost[q]; ok {
+				frontier.Add(q)
+			}
+		}
+	}
+	f.propagate(frontier.ToOrderedList())
+
+	return nil
+}
+
+// ToProto converts a FlowField to a pb.FlowField proto.
+func (f *FlowField) ToProto() *pb.FlowField {
+	rv := &pb.FlowField{
+		Goals: f.Goals.ToProto(),
+	}
+	for _, p := range f.Direction.Domain().ToOrderedList() {
+		rv.Steps = append(rv.Steps, &pb.FlowFieldStep{
+			Coord:     p.ToProto(),
+			Direction: DirectionToProto(f.Direction[p]),
+			Cost:      f.Cost[p],
+		})
+	}
+	return rv
+}
+
+// FlowFieldFromProto converts a pb.FlowField proto to a FlowField. The
+// result cannot be updated, since the costs it was computed from are unknown.
+func FlowFieldFromProto(p *pb.FlowField) (*FlowField, error) {
+	goals, err := HexSetFromProto(p.GetGoals())
+	if err != nil {
+		return nil, err
+	}
+
+	rv := &FlowField{
+		Goals:     goals,
+		Cost:      HexMap[float64]{},
+		Direction: HexMap[HexDir]{},
+	}
+	for _, g := range goals.Enumerate() {
+		rv.Cost[g] = 0
+	}
+	for _, step := range p.GetSteps() {
+		c, err := HexFromProto(step.GetCoord())
+		if err != nil {
+			return nil, err
+		}
+		if goals.Contains(c) {
+			return nil, fmt.Errorf("flow field goal %v has a step", c)
+		}
+		if _, ok := pb.Direction_name[int32(step.Direction)]; !ok {
+			return nil, fmt.Errorf("flow field step at %v has invalid direction %v", c, step.Direction)
+		}
+		rv.Cost[c] = step.Cost
+		rv.Direction[c] = DirectionFromProto(step.Direction)
+	}
+	return rv, nil
+}
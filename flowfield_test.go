@@ -0,0 +1,161 @@
+package hex
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	pb "github.com/steinarvk/above-hex/hexpb"
+)
+
+func checkFlowField(t *testing.T, f *FlowField, cost func(HexCoord, HexCoord) (float64, bool)) {
+	for p, c := range f.Cost {
+		next, ok := f.Next(p)
+		if f.Goals.Contains(p) {
+			if ok || c != 0 {
+				t.Errorf("goal %v has cost %v or a next step", p, c)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("%v has no next step", p)
+			continue
+		}
+		step, ok := cost(p, next)
+		if !ok || f.Cost[next]+step != c {
+			t.Errorf("step from %v to %v does not match costs %v and %v", p, next, c, f.Cost[next])
+		}
+	}
+}
+
+func TestFlowField(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	weights := map[HexCoord]float64{}
+	region := NewHexSetAround(Origin, 8)
+	for _, p := range region.ToOrderedList() {
+		weights[p] = float64(1 + r.Intn(4))
+	}
+	cost := func(a, b HexCoord) (float64, bool) {
+		w := weights[b]
+		return w, w > 0
+	}
+
+	params := &FlowFieldParams{
+		Goals:  NewHexSetSingleton(Origin),
+		Region: region,
+		Cost:   cost,
+	}
+	f, err := NewFlowField(params)
+	if err != nil {
+		t.Fatalf("NewFlowField failed: %v", err)
+	}
+	if len(f.Cost) != region.Size() {
+		t.Errorf("expected all %d hexes to be reached, got %d", region.Size(), len(f.Cost))
+	}
+	checkFlowField(t, f, cost)
+
+	for i := 0; i < 10; i++ {
+		changed := NewHexSet()
+		for j := 0; j < 5; j++ {
+			p, _ := region.RandomFrom(r)
+			if p == Origin {
+				continue
+			}
+			weights[p] = float64(r.Intn(6))
+			changed.Add(p)
+		}
+
+		if err := f.Update(changed); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		checkFlowField(t, f, cost)
+
+		fresh, _ := NewFlowField(params)
+		if !reflect.DeepEqual(f.Cost, fresh.Cost) {
+			t.Fatalf("updated flow field differs from recomputed flow field")
+		}
+	}
+}
+
+func TestFlowFieldUpdateAtGoal(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	weights := map[HexCoord]float64{}
+	region := NewHexSetAround(Origin, 6)
+	for _, p := range region.ToOrderedList() {
+		weights[p] = 1
+	}
+	cost := func(a, b HexCoord) (float64, bool) {
+		w := weights[b]
+		return w, w > 0
+	}
+
+	goals := NewHexSet()
+	goals.Add(Origin)
+	goals.AddHex(4, 4)
+	params := &FlowFieldParams{
+		Goals:  goals,
+		Region: region,
+		Cost:   cost,
+	}
+	f, err := NewFlowField(params)
+	if err != nil {
+		t.Fatalf("NewFlowField failed: %v", err)
+	}
+
+	// Stepping into the goal becomes more expensive.
+	weights[Origin] = 4
+	if err := f.Update(NewHexSetSingleton(Origin)); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if c := f.Cost[NewHex(0, 2)]; c != 4 {
+		t.Errorf("expected (0,2) to cost 4 after the goal changed, got %v", c)
+	}
+
+	for i := 0; i < 200; i++ {
+		p, _ := goals.RandomFrom(r)
+		if r.Intn(2) == 0 {
+			p, _ = region.RandomFrom(r)
+		}
+		weights[p] = float64(r.Intn(6))
+		if err := f.Update(NewHexSetSingleton(p)); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		checkFlowField(t, f, cost)
+
+		fresh, _ := NewFlowField(params)
+		if !reflect.DeepEqual(f.Cost, fresh.Cost) {
+			t.Fatalf("updated flow field differs from recomputed flow field after %d edits", i+1)
+		}
+	}
+}
+
+func TestFlowFieldProto(t *testing.T) {
+	goals := NewHexSet()
+	goals.AddHex(0, 0)
+	goals.AddHex(3, 1)
+	f, err := NewFlowField(&FlowFieldParams{
+		Goals:   goals,
+		Cost:    func(a, b HexCoord) (float64, bool) { return 1, true },
+		MaxCost: 4,
+	})
+	if err != nil {
+		t.Fatalf("NewFlowField failed: %v", err)
+	}
+
+	g, err := FlowFieldFromProto(f.ToProto())
+	if err != nil {
+		t.Fatalf("FlowFieldFromProto failed: %v", err)
+	}
+	if !reflect.DeepEqual(f.Cost, g.Cost) || !reflect.DeepEqual(f.Direction, g.Direction) || !f.Goals.Equals(g.Goals) {
+		t.Errorf("flow field changed in proto round trip")
+	}
+	if err := g.Update(goals); err == nil {
+		t.Errorf("expected error updating flow field from proto")
+	}
+
+	bad := f.ToProto()
+	bad.Steps[0].Direction = pb.Direction(6)
+	if _, err := FlowFieldFromProto(bad); err == nil {
+		t.Errorf("expected error decoding a step with an invalid direction")
+	}
+}
@@ -83,3 +83,26 @@ func DirectionFromProto(d pb.Direction) HexDir {
 		panic(fmt.Errorf("unknown direction to proto: %v", d))
 	}
 }
+
+// Rotated returns the direction a number of 60-degree steps counterclockwise
+// from a HexDir (or clockwise, for negative steps).
+func (d HexDir) Rotated(steps int) HexDir {
+	return OrderedDirections[((int(d)+steps)%6+6)%6]
+}
+
+// Opposite returns the direction opposite to a HexDir.
+func (d HexDir) Opposite() HexDir {
+	return d.Rotated(3)
+}
+
+// DirectionBetween finds the HexDir of the step from a HexCoord to an
+// adjacent one. An error is returned if they are not adjacent.
+func DirectionBetween(from, to HexCoord) (HexDir, error) {
+	delta := to.Minus(from)
+	for _, d := range OrderedDirections {
+		if Directions[d] == delta {
+			return d, nil
+		}
+	}
+	return North, fmt.Errorf("%v and %v are not adjacent", from, to)
+}
@@ -118,6 +118,12 @@ func (c *HexCoord) Radius() int {
 	return bigSteps + smallSteps
 }
 
+// StepsTo computes the number of steps between two HexCoords.
+func (c HexCoord) StepsTo(p HexCoord) int {
+	d := p.Minus(c)
+	return d.Radius()
+}
+
 // Negation computes the negation of the HexCoord (i.e. scaled by -1).
 func (c HexCoord) Negation() HexCoord {
 	return Origin.AddMultDelta(-1, c)
@@ -201,3 +207,18 @@ func (a HexCoord) Less(b HexCoord) bool {
 	}
 	return false
 }
+
+// Rotated rotates the HexCoord counterclockwise around the origin, by a
+// number of 60-degree steps. Negative steps rotate clockwise.
+func (c HexCoord) Rotated(steps int) HexCoord {
+	steps = ((steps % 6) + 6) % 6
+	for i := 0; i < steps; i++ {
+		c = HexCoord{(c.X - c.Y) / 2, (3*c.X + c.Y) / 2}
+	}
+	return c
+}
+
+// Mirrored reflects the HexCoord across the vertical (Y) axis.
+func (c HexCoord) Mirrored() HexCoord {
+	return HexCoord{-c.X, c.Y}
+}
@@ -0,0 +1,25 @@
+package hex
+
+// HexMap is a mapping from HexCoords to values.
+type HexMap[V any] map[HexCoord]V
+
+// Domain computes the HexSet of HexCoords that have values in the HexMap.
+func (m HexMap[V]) Domain() *HexSet {
+	rv := NewHexSet()
+	for p := range m {
+		rv.Add(p)
+	}
+	return rv
+}
+
+// Where computes the HexSet of HexCoords p in the HexMap where f(p, m[p])
+// returns true.
+func (m HexMap[V]) Where(f func(HexCoord, V) bool) *HexSet {
+	rv := NewHexSet()
+	for p, v := range m {
+		if f(p, v) {
+			rv.Add(p)
+		}
+	}
+	return rv
+}
@@ -11,6 +11,10 @@ It is generated from these files:
 It has these top-level messages:
 	HexCoord
 	HexSet
+	HexSetDiff
+	CompactHexSet
+	FlowFieldStep
+	FlowField
 */
 package hexpb
 
@@ -86,25 +90,118 @@ func (m *HexSet) GetCoords() []*HexCoord {
 	return nil
 }
 
+type HexSetDiff struct {
+	Added   *HexSet `protobuf:"bytes,1,opt,name=added" json:"added,omitempty"`
+	Removed *HexSet `protobuf:"bytes,2,opt,name=removed" json:"removed,omitempty"`
+}
+
+func (m *HexSetDiff) Reset()                    { *m = HexSetDiff{} }
+func (m *HexSetDiff) String() string            { return proto.CompactTextString(m) }
+func (*HexSetDiff) ProtoMessage()               {}
+func (*HexSetDiff) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *HexSetDiff) GetAdded() *HexSet {
+	if m != nil {
+		return m.Added
+	}
+	return nil
+}
+
+func (m *HexSetDiff) GetRemoved() *HexSet {
+	if m != nil {
+		return m.Removed
+	}
+	return nil
+}
+
+// CompactHexSet is a run-length encoding of a HexSet. Each run is a
+// triple (dx, dy, n) of n hexes spaced two apart along a column: dx is the
+// offset from the previous run's X; dy is the gap after the previous run's
+// last hex if in the same column, otherwise the offset from its first Y.
+type CompactHexSet struct {
+	Runs []int32 `protobuf:"zigzag32,1,rep,packed,name=runs" json:"runs,omitempty"`
+}
+
+func (m *CompactHexSet) Reset()                    { *m = CompactHexSet{} }
+func (m *CompactHexSet) String() string            { return proto.CompactTextString(m) }
+func (*CompactHexSet) ProtoMessage()               {}
+func (*CompactHexSet) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+// FlowFieldStep gives the best direction to step from a hex toward the
+// goals of a FlowField, and the cost of reaching them from there.
+type FlowFieldStep struct {
+	Coord     *HexCoord `protobuf:"bytes,1,opt,name=coord" json:"coord,omitempty"`
+	Direction Direction `protobuf:"varint,2,opt,name=direction,enum=hexpb.Direction" json:"direction,omitempty"`
+	Cost      float64   `protobuf:"fixed64,3,opt,name=cost" json:"cost,omitempty"`
+}
+
+func (m *FlowFieldStep) Reset()                    { *m = FlowFieldStep{} }
+func (m *FlowFieldStep) String() string            { return proto.CompactTextString(m) }
+func (*FlowFieldStep) ProtoMessage()               {}
+func (*FlowFieldStep) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *FlowFieldStep) GetCoord() *HexCoord {
+	if m != nil {
+		return m.Coord
+	}
+	return nil
+}
+
+type FlowField struct {
+	Goals *HexSet          `protobuf:"bytes,1,opt,name=goals" json:"goals,omitempty"`
+	Steps []*FlowFieldStep `protobuf:"bytes,2,rep,name=steps" json:"steps,omitempty"`
+}
+
+func (m *FlowField) Reset()                    { *m = FlowField{} }
+func (m *FlowField) String() string            { return proto.CompactTextString(m) }
+func (*FlowField) ProtoMessage()               {}
+func (*FlowField) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *FlowField) GetGoals() *HexSet {
+	if m != nil {
+		return m.Goals
+	}
+	return nil
+}
+
+func (m *FlowField) GetSteps() []*FlowFieldStep {
+	if m != nil {
+		return m.Steps
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*HexCoord)(nil), "hexpb.HexCoord")
 	proto.RegisterType((*HexSet)(nil), "hexpb.HexSet")
+	proto.RegisterType((*HexSetDiff)(nil), "hexpb.HexSetDiff")
+	proto.RegisterType((*CompactHexSet)(nil), "hexpb.CompactHexSet")
+	proto.RegisterType((*FlowFieldStep)(nil), "hexpb.FlowFieldStep")
+	proto.RegisterType((*FlowField)(nil), "hexpb.FlowField")
 	proto.RegisterEnum("hexpb.Direction", Direction_name, Direction_value)
 }
 
 var fileDescriptor0 = []byte{
-	// 194 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0xe2, 0xcc, 0x48, 0xad, 0xd0,
-	0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x05, 0x32, 0x0b, 0x92, 0x94, 0x14, 0xb8, 0x38, 0x3c,
-	0x52, 0x2b, 0x9c, 0xf3, 0xf3, 0x8b, 0x52, 0x84, 0x38, 0xb9, 0x18, 0x2b, 0x24, 0x18, 0x15, 0x18,
-	0x35, 0x58, 0x41, 0xcc, 0x4a, 0x09, 0x26, 0x10, 0x53, 0x49, 0x93, 0x8b, 0x0d, 0xa8, 0x22, 0x38,
-	0xb5, 0x44, 0x48, 0x9e, 0x8b, 0x2d, 0x19, 0xa4, 0xb0, 0x18, 0xa8, 0x88, 0x59, 0x83, 0xdb, 0x88,
-	0x5f, 0x0f, 0x6c, 0x86, 0x1e, 0xcc, 0x00, 0xad, 0x58, 0x2e, 0x4e, 0x97, 0xcc, 0xa2, 0xd4, 0xe4,
-	0x92, 0xcc, 0xfc, 0x3c, 0xa0, 0x11, 0xac, 0x7e, 0xfe, 0x41, 0x21, 0x1e, 0x02, 0x0c, 0x42, 0xbc,
-	0x5c, 0x9c, 0x60, 0x66, 0xb8, 0x6b, 0x70, 0x88, 0x00, 0x23, 0x88, 0x1b, 0xec, 0x1f, 0x0a, 0xe5,
-	0x32, 0x81, 0x14, 0x82, 0xb9, 0x02, 0xcc, 0x70, 0x19, 0x57, 0x47, 0xa0, 0x0c, 0x0b, 0x5c, 0x1f,
-	0x98, 0xcb, 0xea, 0x24, 0xcf, 0x25, 0x91, 0x9c, 0x9f, 0xab, 0x97, 0x9d, 0x98, 0x93, 0x92, 0x98,
-	0x9e, 0x5a, 0xa4, 0x97, 0x98, 0x94, 0x5f, 0x96, 0x0a, 0x71, 0x83, 0x13, 0x33, 0xd0, 0x11, 0x49,
-	0x6c, 0x60, 0xaf, 0x19, 0x03, 0x02, 0x00, 0x00, 0xff, 0xff, 0x9d, 0x8e, 0x52, 0x66, 0xe7, 0x00,
-	0x00, 0x00,
+	// 327 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x51, 0x4d, 0x4f, 0x83, 0x40,
+	0x14, 0x14, 0x28, 0x55, 0x5e, 0x45, 0x71, 0xe3, 0x61, 0x0f, 0xda, 0x12, 0x7a, 0xa9, 0x1e, 0x38,
+	0xd4, 0x5f, 0x60, 0xbf, 0x42, 0x3c, 0xb4, 0x89, 0xd4, 0x78, 0xf2, 0x00, 0xec, 0x6b, 0x4b, 0xa4,
+	0x5d, 0x02, 0x6b, 0xc5, 0x7f, 0x6f, 0x76, 0x4b, 0xdb, 0x68, 0x7a, 0x7b, 0xc3, 0xcc, 0x9b, 0x99,
+	0xc7, 0x82, 0xb5, 0xc2, 0xca, 0xcf, 0x0b, 0x2e, 0x38, 0x31, 0x57, 0x58, 0xe5, 0xb1, 0xe7, 0xc2,
+	0x45, 0x80, 0xd5, 0x90, 0xf3, 0x82, 0x11, 0x0b, 0xb4, 0x8a, 0x6a, 0xae, 0xd6, 0x33, 0xe5, 0xf8,
+	0x43, 0x75, 0x39, 0x7a, 0x0f, 0xd0, 0x0c, 0xb0, 0x0a, 0x51, 0x90, 0x0e, 0x34, 0x13, 0x29, 0x2c,
+	0xa9, 0xe6, 0x1a, 0xbd, 0x56, 0xff, 0xda, 0x57, 0x1e, 0xfe, 0xde, 0xc0, 0x7b, 0x01, 0xd8, 0x49,
+	0x47, 0xe9, 0x62, 0x41, 0xee, 0xc0, 0x8c, 0x18, 0x43, 0xa6, 0x2c, 0x5b, 0x7d, 0xfb, 0xa8, 0x96,
+	0x66, 0x6d, 0x38, 0x2f, 0x70, 0xcd, 0xb7, 0xc8, 0xa8, 0x7e, 0x82, 0xf7, 0xee, 0xc1, 0x1e, 0xf2,
+	0x75, 0x1e, 0x25, 0xa2, 0x5e, 0xb8, 0x84, 0x46, 0xf1, 0xb5, 0xd9, 0x65, 0xdf, 0x78, 0x31, 0xd8,
+	0x93, 0x8c, 0x7f, 0x4f, 0x52, 0xcc, 0x58, 0x28, 0x30, 0x27, 0x6d, 0x30, 0x55, 0xb9, 0x3a, 0xed,
+	0x7f, 0x37, 0xd2, 0x05, 0x8b, 0xa5, 0x05, 0x26, 0x22, 0xe5, 0x1b, 0x95, 0x78, 0xd5, 0x77, 0x6a,
+	0xcd, 0x68, 0xff, 0x5d, 0x66, 0x24, 0xbc, 0x14, 0xd4, 0x70, 0xb5, 0x9e, 0xe6, 0x4d, 0xc1, 0x3a,
+	0x64, 0xc8, 0x6b, 0x96, 0x3c, 0xca, 0xca, 0xd3, 0xd7, 0x74, 0xc1, 0x2c, 0x05, 0xe6, 0x25, 0xd5,
+	0xd5, 0x9f, 0xb9, 0xad, 0xd9, 0x3f, 0x15, 0x1f, 0x3f, 0xc0, 0x3a, 0x46, 0x59, 0x60, 0x4e, 0x67,
+	0xaf, 0xf3, 0xc0, 0x39, 0x23, 0x36, 0x58, 0x6a, 0x7c, 0x1f, 0x87, 0x73, 0x47, 0x93, 0x30, 0x9c,
+	0xbd, 0xd5, 0x50, 0x97, 0x42, 0x05, 0x1d, 0xe3, 0xc0, 0x8c, 0x9f, 0xc3, 0xb9, 0xd3, 0x38, 0xec,
+	0x29, 0x68, 0x0e, 0x3a, 0x40, 0x13, 0xbe, 0xf6, 0x3f, 0xa3, 0x8c, 0x45, 0x4b, 0x2c, 0xfc, 0x28,
+	0xe6, 0x5b, 0xdc, 0x15, 0x19, 0x18, 0x01, 0x56, 0x71, 0x53, 0xbd, 0xfc, 0xd3, 0xef, 0x00, 0xbf,
+	0xa9, 0x93, 0x33, 0x06, 0x02, 0x00, 0x00,
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/bradfitz/slice"
 	"log"
 	"math"
+	"sort"
 	"strings"
 
 	pb "github.com/steinarvk/above-hex/hexpb"
@@ -172,6 +173,7 @@ type HexSet struct {
 	impl          hexSetIntf
 	frozen        bool
 	cloneChildren []*HexSet
+	ordered       []HexCoord
 }
 
 // Freeze marks the HexSet as immutable.
@@ -196,9 +198,18 @@ func (s *HexSet) ensureThawed() {
 		rv := s.explicitClone()
 		rv.Freeze()
 		for _, ch := range s.cloneChildren {
-			ch.impl = &hexSetModifiedClone{
+			// The children keep their own changes, which are relative to
+			// the contents of s before this modification, and so to rv.
+			impl := &hexSetModifiedClone{
 				frozenHexSet: rv,
 			}
+			if old, ok := ch.impl.(*hexSetModifiedClone); ok && old.mutated {
+				impl.mutated = true
+				impl.additions = old.additions
+				impl.removals = old.removals
+			}
+			ch.impl = impl
+			ch.ordered = nil
 		}
 		s.cloneChildren = nil
 	}
@@ -234,31 +245,89 @@ func (h *HexSet) Enumerate() []HexCoord {
 	return h.impl.Enumerate()
 }
 
-// Random selects a random HexCoord from a HexSet.
+// Random selects a random HexCoord from a HexSet, using the global random
+// source. Use RandomFrom for reproducible selection.
 func (h *HexSet) Random() (HexCoord, error) {
 	return h.RandomFrom(rand.New(rand.NewSource(rand.Int63())))
 }
 
 // RandomFrom selects a random HexCoord from a HexSet, using a specified
-// rand.Rand.
+// rand.Rand. The choice depends only on the contents of the set and the state
+// of the rand.Rand, so it is reproducible given a seed.
 func (h *HexSet) RandomFrom(r *rand.Rand) (HexCoord, error) {
-	elements := h.Enumerate()
+	elements := h.orderedList()
 
-	n := int32(len(elements))
+	n := len(elements)
 	if n == 0 {
 		return Origin, fmt.Errorf("random choice from empty set")
 	}
 
-	i := r.Int31n(n)
-	for _, v := range elements {
-		if i == 0 {
-			return v, nil
+	return elements[r.Intn(n)], nil
+}
+
+// WeightedRandomFrom selects a random HexCoord from a HexSet, with each
+// HexCoord p chosen with probability proportional to weight(p). Like
+// RandomFrom, the choice is reproducible given a seed.
+func (h *HexSet) WeightedRandomFrom(r *rand.Rand, weight func(HexCoord) float64) (HexCoord, error) {
+	elements := h.orderedList()
+
+	weights := make([]float64, len(elements))
+	cumulative := make([]float64, len(elements))
+	total := 0.0
+	for i, p := range elements {
+		w := weight(p)
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return Origin, fmt.Errorf("invalid weight %v for %v", w, p)
 		}
+		weights[i] = w
+		total += w
+		cumulative[i] = total
+	}
+
+	if total <= 0 {
+		return Origin, fmt.Errorf("weighted random choice with no positive weights")
+	}
 
+	x := r.Float64() * total
+	i := sort.Search(len(cumulative), func(i int) bool {
+		return cumulative[i] > x
+	})
+	if i == len(cumulative) {
+		i--
+	}
+	for weights[i] == 0 {
 		i--
 	}
 
-	return Origin, fmt.Errorf("impossible choice was made in set selection")
+	return elements[i], nil
+}
+
+// SampleFrom selects k distinct random HexCoords from a HexSet (i.e. without
+// replacement), using a specified rand.Rand. Like RandomFrom, the choice is
+// reproducible given a seed. The order of the returned HexCoords is not
+// itself random.
+func (h *HexSet) SampleFrom(r *rand.Rand, k int) ([]HexCoord, error) {
+	elements := h.orderedList()
+
+	n := len(elements)
+	if k < 0 || k > n {
+		return nil, fmt.Errorf("cannot sample %d elements from set of size %d", k, n)
+	}
+
+	// Floyd's algorithm: for each of the last k positions j, choose among the
+	// first j+1 elements, taking element j if the choice was already taken.
+	chosen := map[int]bool{}
+	rv := make([]HexCoord, 0, k)
+	for j := n - k; j < n; j++ {
+		i := r.Intn(j + 1)
+		if chosen[i] {
+			i = j
+		}
+		chosen[i] = true
+		rv = append(rv, elements[i])
+	}
+
+	return rv, nil
 }
 
 // PickArbitrary picks an arbitrary HexCoord from a HexSet.
@@ -276,6 +345,14 @@ func (h *HexSet) ToList() []HexCoord {
 
 // ToOrderedList converts a HexSet to an ordered list of HexCoords.
 func (h *HexSet) ToOrderedList() []HexCoord {
+	if h.ordered != nil {
+		return append([]HexCoord{}, h.ordered...)
+	}
+	return h.sortedList()
+}
+
+// sortedList computes a new list of the HexCoords of the HexSet in order.
+func (h *HexSet) sortedList() []HexCoord {
 	rv := []HexCoord{}
 
 	for _, p := range h.Enumerate() {
@@ -289,6 +366,26 @@ func (h *HexSet) ToOrderedList() []HexCoord {
 	return rv
 }
 
+// orderedList returns the HexCoords of the HexSet in order, for the random
+// selection functions. Once computed, the list is cached and kept up to date
+// as the HexSet is modified, so that repeatedly choosing and removing hexes
+// stays cheap; sets that are never sampled do not pay for maintaining it.
+// The list must not be mutated by callers.
+func (h *HexSet) orderedList() []HexCoord {
+	if h.ordered == nil {
+		h.ordered = h.sortedList()
+	}
+	return h.ordered
+}
+
+// orderedIndex finds the position of a HexCoord in the cached ordered list,
+// or the position at which it would be inserted.
+func (h *HexSet) orderedIndex(x HexCoord) int {
+	return sort.Search(len(h.ordered), func(i int) bool {
+		return !h.ordered[i].Less(x)
+	})
+}
+
 // Contains checks whether a HexSet contains a certain HexCoord.
 func (h *HexSet) Contains(x HexCoord) bool {
 	return h.impl.Contains(x)
@@ -388,7 +485,7 @@ func (h *HexSet) Intersection(x *HexSet) *HexSet {
 // InPlaceUnion mutates the HexSet to take the union with another.
 func (h *HexSet) InPlaceUnion(x *HexSet) {
 	for _, p := range x.Enumerate() {
-		h.impl.Add(p)
+		h.Add(p)
 	}
 }
 
@@ -407,9 +504,50 @@ func (h *HexSet) Union(x *HexSet) *HexSet {
 	return rv
 }
 
+// Equals checks whether two HexSets contain exactly the same HexCoords.
+func (h *HexSet) Equals(x *HexSet) bool {
+	return h.Size() == x.Size() && h.ContainsSet(x)
+}
+
+func (h *HexSet) mapped(f func(HexCoord) HexCoord) *HexSet {
+	rv := NewHexSet()
+	for _, p := range h.Enumerate() {
+		rv.Add(f(p))
+	}
+	return rv
+}
+
+// Translated computes a new HexSet with a HexCoord delta added to each
+// element.
+func (h *HexSet) Translated(offset HexCoord) *HexSet {
+	return h.mapped(func(p HexCoord) HexCoord {
+		return p.AddDelta(offset)
+	})
+}
+
+// Rotated computes a new HexSet with each element rotated counterclockwise
+// around the origin by a number of 60-degree steps.
+func (h *HexSet) Rotated(steps int) *HexSet {
+	return h.mapped(func(p HexCoord) HexCoord {
+		return p.Rotated(steps)
+	})
+}
+
+// Mirrored computes a new HexSet with each element reflected across the
+// vertical (Y) axis.
+func (h *HexSet) Mirrored() *HexSet {
+	return h.mapped(HexCoord.Mirrored)
+}
+
 // Add adds a HexCoord to the HexSet.
 func (h *HexSet) Add(x HexCoord) {
 	h.ensureThawed()
+	if h.ordered != nil && !h.impl.Contains(x) {
+		i := h.orderedIndex(x)
+		h.ordered = append(h.ordered, HexCoord{})
+		copy(h.ordered[i+1:], h.ordered[i:])
+		h.ordered[i] = x
+	}
 	h.impl.Add(x)
 }
 
@@ -434,6 +572,10 @@ func (h *HexSet) ContainsHex(i, j int) bool {
 // Remove removes a HexCoord from the HexSet.
 func (h *HexSet) Remove(x HexCoord) {
 	h.ensureThawed()
+	if h.ordered != nil && h.impl.Contains(x) {
+		i := h.orderedIndex(x)
+		h.ordered = append(h.ordered[:i], h.ordered[i+1:]...)
+	}
 	h.impl.Remove(x)
 }
 
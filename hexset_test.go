@@ -1,7 +1,9 @@
 package hex
 
 import (
+	"math/rand"
 	"reflect"
+	"sort"
 	"testing"
 )
 
@@ -119,3 +121,172 @@ func TestCloneSemanticsBasicsFrozen(t *testing.T) {
 		t.Errorf("modification on clone changed original, should not contain %v", p)
 	}
 }
+
+func TestRandomFromIsReproducible(t *testing.T) {
+	a := NewHexSetAround(Origin, 5)
+	b := NewHexSet()
+	list := a.ToList()
+	for i := len(list) - 1; i >= 0; i-- {
+		b.Add(list[i])
+	}
+
+	ra := rand.New(rand.NewSource(42))
+	rb := rand.New(rand.NewSource(42))
+	for i := 0; i < 50; i++ {
+		pa, err := a.RandomFrom(ra)
+		if err != nil {
+			t.Fatalf("RandomFrom failed: %v", err)
+		}
+		pb, err := b.RandomFrom(rb)
+		if err != nil {
+			t.Fatalf("RandomFrom failed: %v", err)
+		}
+		if pa != pb {
+			t.Fatalf("same seed gave different choices from equal sets: %v and %v", pa, pb)
+		}
+		a.Remove(pa)
+		b.Remove(pb)
+	}
+}
+
+func TestRandomFromCloneAfterParentChanges(t *testing.T) {
+	parent := NewHexSet()
+	parent.AddHex(0, 0)
+	clone := parent.Clone()
+	clone.AddHex(0, 2)
+	if got := clone.ToOrderedList(); len(got) != 2 {
+		t.Fatalf("expected two hexes in clone, got %v", got)
+	}
+
+	parent.AddHex(0, 4)
+
+	expected := []HexCoord{NewHex(0, 0), NewHex(0, 2)}
+	if got := clone.ToOrderedList(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected clone to keep its contents %v, got %v", expected, got)
+	}
+	if clone.ContainsHex(0, 4) {
+		t.Errorf("clone picked up a hex added to its parent")
+	}
+
+	r := rand.New(rand.NewSource(42))
+	for i := 0; i < 20; i++ {
+		p, err := clone.RandomFrom(r)
+		if err != nil {
+			t.Fatalf("RandomFrom failed: %v", err)
+		}
+		if !clone.Contains(p) {
+			t.Fatalf("RandomFrom returned %v, which is not in the clone", p)
+		}
+	}
+}
+
+func TestOrderedListFollowsChanges(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := NewHexSet()
+	// Sampling caches the ordered list, which must then follow changes.
+	s.RandomFrom(r)
+	disk := HexDisk(4)
+	for i := 0; i < 500; i++ {
+		p := disk[r.Intn(len(disk))]
+		if r.Intn(2) == 0 {
+			s.Add(p)
+		} else {
+			s.Remove(p)
+		}
+
+		got := s.ToOrderedList()
+		expected := s.Enumerate()
+		sort.Slice(expected, func(i, j int) bool { return expected[i].Less(expected[j]) })
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("ordered list %v does not match contents %v", got, expected)
+		}
+	}
+}
+
+func BenchmarkRandomFromAndRemove(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		s := NewHexSet()
+		for _, p := range HexDisk(30) {
+			s.Add(p)
+		}
+		b.StartTimer()
+		for s.Size() > 0 {
+			p, _ := s.RandomFrom(r)
+			s.Remove(p)
+		}
+	}
+}
+
+func BenchmarkToOrderedListAndAdd(b *testing.B) {
+	// Reading the ordered list of a large set must not make later
+	// additions expensive.
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		s := NewHexSet()
+		for _, p := range HexDisk(100) {
+			s.Add(p)
+		}
+		b.StartTimer()
+		for round := 0; round < 3; round++ {
+			s.ToOrderedList()
+			for r := 101 + 10*round; r <= 110+10*round; r++ {
+				for _, p := range HexCircle(r) {
+					s.Add(p)
+				}
+			}
+		}
+	}
+}
+
+func TestWeightedRandomFrom(t *testing.T) {
+	s := NewHexSetAround(Origin, 2)
+	r := rand.New(rand.NewSource(42))
+	weight := func(p HexCoord) float64 {
+		if p.Y > 0 {
+			return 0
+		}
+		return float64(p.Radius() + 1)
+	}
+
+	for i := 0; i < 100; i++ {
+		p, err := s.WeightedRandomFrom(r, weight)
+		if err != nil {
+			t.Fatalf("WeightedRandomFrom failed: %v", err)
+		}
+		if weight(p) == 0 {
+			t.Errorf("chose %v, which has zero weight", p)
+		}
+	}
+
+	if _, err := s.WeightedRandomFrom(r, func(HexCoord) float64 { return 0 }); err == nil {
+		t.Errorf("expected error when all weights are zero")
+	}
+}
+
+func TestSampleFrom(t *testing.T) {
+	s := NewHexSetAround(Origin, 2)
+	r := rand.New(rand.NewSource(42))
+
+	for k := 0; k <= s.Size(); k++ {
+		sample, err := s.SampleFrom(r, k)
+		if err != nil {
+			t.Fatalf("SampleFrom failed: %v", err)
+		}
+		seen := NewHexSet()
+		for _, p := range sample {
+			if seen.Contains(p) || !s.Contains(p) {
+				t.Errorf("sample %v has duplicate or foreign element %v", sample, p)
+			}
+			seen.Add(p)
+		}
+		if len(sample) != k {
+			t.Errorf("expected sample of size %d, got %v", k, sample)
+		}
+	}
+
+	if _, err := s.SampleFrom(r, s.Size()+1); err == nil {
+		t.Errorf("expected error when sampling more elements than the set has")
+	}
+}
@@ -0,0 +1,125 @@
+package hex
+
+import (
+	pb "github.com/steinarvk/above-hex/hexpb"
+)
+
+// HexSetDiff represents the changes between two HexSets: the HexCoords that
+// were added and the HexCoords that were removed.
+type HexSetDiff struct {
+	Added   *HexSet
+	Removed *HexSet
+}
+
+// NewHexSetDiff creates a new (empty) HexSetDiff.
+func NewHexSetDiff() *HexSetDiff {
+	return &HexSetDiff{
+		Added:   NewHexSet(),
+		Removed: NewHexSet(),
+	}
+}
+
+// cloneDiff returns the changes recorded by a HexSet that is an unrescued
+// Clone of another, or nil if it is not.
+func cloneDiff(from, to *HexSet) *HexSetDiff {
+	impl, ok := to.impl.(*hexSetModifiedClone)
+	if !ok || impl.frozenHexSet != from {
+		return nil
+	}
+	if !impl.mutated {
+		return NewHexSetDiff()
+	}
+	return &HexSetDiff{
+		Added:   impl.additions.explicitClone(),
+		Removed: impl.removals.explicitClone(),
+	}
+}
+
+// DiffHexSets computes the changes needed to turn one HexSet into another.
+// This is cheap if one of the sets is a Clone of the other.
+func DiffHexSets(from, to *HexSet) *HexSetDiff {
+	if rv := cloneDiff(from, to); rv != nil {
+		return rv
+	}
+	if rv := cloneDiff(to, from); rv != nil {
+		rv.Added, rv.Removed = rv.Removed, rv.Added
+		return rv
+	}
+
+	return &HexSetDiff{
+		Added:   to.Difference(from),
+		Removed: from.Difference(to),
+	}
+}
+
+// IsEmpty checks whether the HexSetDiff contains no changes.
+func (d *HexSetDiff) IsEmpty() bool {
+	return d.Added.Size() == 0 && d.Removed.Size() == 0
+}
+
+// Apply mutates a HexSet by applying the changes in the HexSetDiff.
+func (d *HexSetDiff) Apply(s *HexSet) {
+	for _, p := range d.Removed.Enumerate() {
+		s.Remove(p)
+	}
+	s.InPlaceUnion(d.Added)
+}
+
+// Applied computes a new HexSet that is like the old one, except with the
+// changes in the HexSetDiff applied.
+func (d *HexSetDiff) Applied(s *HexSet) *HexSet {
+	rv := s.Clone()
+	d.Apply(rv)
+	return rv
+}
+
+// Inverse computes a HexSetDiff that undoes this one. The result does not
+// share any sets with this one.
+func (d *HexSetDiff) Inverse() *HexSetDiff {
+	return &HexSetDiff{
+		Added:   d.Removed.explicitClone(),
+		Removed: d.Added.explicitClone(),
+	}
+}
+
+// Compose computes a single HexSetDiff equivalent to applying this one and
+// then another. The second diff is assumed to describe changes relative to
+// the result of the first (as it would if computed with DiffHexSets).
+func (d *HexSetDiff) Compose(next *HexSetDiff) *HexSetDiff {
+	added := d.Added.Difference(next.Removed)
+	added.InPlaceUnion(next.Added.Difference(d.Removed))
+
+	removed := d.Removed.Difference(next.Added)
+	removed.InPlaceUnion(next.Removed.Difference(d.Added))
+
+	return &HexSetDiff{
+		Added:   added,
+		Removed: removed,
+	}
+}
+
+// ToProto converts a HexSetDiff to a pb.HexSetDiff proto.
+func (d *HexSetDiff) ToProto() *pb.HexSetDiff {
+	return &pb.HexSetDiff{
+		Added:   d.Added.ToProto(),
+		Removed: d.Removed.ToProto(),
+	}
+}
+
+// HexSetDiffFromProto converts a pb.HexSetDiff proto to a HexSetDiff.
+func HexSetDiffFromProto(p *pb.HexSetDiff) (*HexSetDiff, error) {
+	added, err := HexSetFromProto(p.GetAdded())
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := HexSetFromProto(p.GetRemoved())
+	if err != nil {
+		return nil, err
+	}
+
+	return &HexSetDiff{
+		Added:   added,
+		Removed: removed,
+	}, nil
+}
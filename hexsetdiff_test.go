@@ -0,0 +1,95 @@
+package hex
+
+import (
+	"testing"
+)
+
+func TestDiffHexSetsRoundTrip(t *testing.T) {
+	a := NewHexSetAround(Origin, 2)
+	b := NewHexSetAround(NewHex(1, 1), 2)
+
+	d := DiffHexSets(a, b)
+	if got := d.Applied(a); !got.Equals(b) {
+		t.Errorf("applying diff to %v gave %v, expected %v", a.ToList(), got.ToList(), b.ToList())
+	}
+	if got := d.Inverse().Applied(b); !got.Equals(a) {
+		t.Errorf("applying inverse diff to %v gave %v, expected %v", b.ToList(), got.ToList(), a.ToList())
+	}
+	if d.Added.Intersects(a) || !a.ContainsSet(d.Removed) {
+		t.Errorf("diff is not minimal: added %v, removed %v", d.Added.ToList(), d.Removed.ToList())
+	}
+}
+
+func TestDiffHexSetsFromClone(t *testing.T) {
+	a := NewHexSetAround(Origin, 1)
+	a.Freeze()
+
+	b := a.Clone()
+	b.AddHex(0, 4)
+	b.RemoveHex(0, 0)
+	b.AddHex(0, 6)
+	b.RemoveHex(0, 6)
+
+	d := DiffHexSets(a, b)
+	if d.Added.Size() != 1 || !d.Added.ContainsHex(0, 4) {
+		t.Errorf("expected only (0,4) to be added, got %v", d.Added.ToList())
+	}
+	if d.Removed.Size() != 1 || !d.Removed.ContainsHex(0, 0) {
+		t.Errorf("expected only (0,0) to be removed, got %v", d.Removed.ToList())
+	}
+
+	rev := DiffHexSets(b, a)
+	if !rev.Added.ContainsHex(0, 0) || !rev.Removed.ContainsHex(0, 4) {
+		t.Errorf("expected reverse diff to be inverse, got added %v, removed %v", rev.Added.ToList(), rev.Removed.ToList())
+	}
+
+	b.AddHex(2, 2)
+	if d.Added.ContainsHex(2, 2) {
+		t.Errorf("diff was changed by later modification of clone")
+	}
+}
+
+func TestHexSetDiffInverseIsIndependent(t *testing.T) {
+	d := DiffHexSets(NewHexSetAround(Origin, 1), NewHexSetAround(NewHex(1, 1), 1))
+	inverse := d.Inverse()
+	added, removed := inverse.Added.ToList(), inverse.Removed.ToList()
+
+	d.Added.AddHex(0, 10)
+	d.Removed.AddHex(0, 12)
+	if len(inverse.Added.ToList()) != len(added) || len(inverse.Removed.ToList()) != len(removed) {
+		t.Errorf("inverse diff was changed by modifying the original")
+	}
+}
+
+func TestHexSetDiffCompose(t *testing.T) {
+	a := NewHexSetAround(Origin, 1)
+	b := a.Clone()
+	b.AddHex(0, 4)
+	b.RemoveHex(1, 1)
+	c := b.Clone()
+	c.RemoveHex(0, 4)
+	c.AddHex(1, 1)
+	c.AddHex(3, 3)
+
+	d := DiffHexSets(a, b).Compose(DiffHexSets(b, c))
+	if got := d.Applied(a); !got.Equals(c) {
+		t.Errorf("composed diff gave %v, expected %v", got.ToList(), c.ToList())
+	}
+	if d.Added.Size() != 1 || d.Removed.Size() != 0 {
+		t.Errorf("expected composed diff to only add (3,3), got added %v, removed %v", d.Added.ToList(), d.Removed.ToList())
+	}
+}
+
+func TestHexSetDiffProto(t *testing.T) {
+	d := NewHexSetDiff()
+	d.Added.AddHex(1, 1)
+	d.Removed.AddHex(0, 2)
+
+	rv, err := HexSetDiffFromProto(d.ToProto())
+	if err != nil {
+		t.Fatalf("HexSetDiffFromProto failed: %v", err)
+	}
+	if !rv.Added.Equals(d.Added) || !rv.Removed.Equals(d.Removed) {
+		t.Errorf("proto round trip gave added %v, removed %v", rv.Added.ToList(), rv.Removed.ToList())
+	}
+}
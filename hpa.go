@@ -0,0 +1,403 @@
+package hex
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ChunkShape selects how a HierarchicalPlanner partitions its map.
+type ChunkShape int
+
+const (
+	// RectangularChunks partitions the map into chunks ChunkSize columns
+	// wide and ChunkSize rows high.
+	RectangularChunks ChunkShape = iota
+	// HexagonalChunks partitions the map into hexagons of radius
+	// ChunkSize, which tile the grid exactly.
+	HexagonalChunks
+)
+
+// ChunkID identifies a chunk of a HierarchicalPlanner's map.
+type ChunkID struct {
+	I, J int
+}
+
+func (a ChunkID) less(b ChunkID) bool {
+	if a.I != b.I {
+		return a.I < b.I
+	}
+	return a.J < b.J
+}
+
+// HierarchicalParams provides parameters for a HierarchicalPlanner. Region
+// is the map, and paths never leave it. Cost is as for AStarParams, and
+// should be symmetric; the planner then finds a path whenever one exists.
+// The Heuristic is optional, and estimates the cost between two hexes.
+type HierarchicalParams struct {
+	Region    *HexSet
+	Cost      func(HexCoord, HexCoord) (float64, bool)
+	Heuristic func(HexCoord, HexCoord) float64
+	Shape     ChunkShape
+	ChunkSize int
+}
+
+type hpaEdge struct {
+	to   HexCoord
+	cost float64
+}
+
+type hpaTransition struct {
+	from, to HexCoord
+	cost     float64
+}
+
+type hpaChunkPair struct {
+	a, b ChunkID
+}
+
+func newHPAChunkPair(a, b ChunkID) hpaChunkPair {
+	if b.less(a) {
+		a, b = b, a
+	}
+	return hpaChunkPair{a, b}
+}
+
+type hpaChunk struct {
+	cells      *HexSet
+	neighbours []ChunkID
+	// entrances are the abstract nodes in the chunk: the hexes at either
+	// end of a transition to or from a neighbouring chunk.
+	entrances []HexCoord
+	// edges are the cheapest paths between entrances within the chunk,
+	// and exits the transitions out of the chunk.
+	edges map[HexCoord][]hpaEdge
+	exits map[HexCoord][]hpaEdge
+}
+
+// HierarchicalPlanner answers long path queries on large maps quickly, using
+// hierarchical pathfinding (HPA*). The map is partitioned into chunks, and
+// each contiguous stretch of border between two chunks gets one transition.
+// The planner precomputes the cheapest paths between the transitions of
+// each chunk, searches this abstract graph, and then refines the result
+// with searches within single chunks. Paths are usually slightly more
+// expensive than the cheapest.
+type HierarchicalPlanner struct {
+	params      *HierarchicalParams
+	chunks      map[ChunkID]*hpaChunk
+	transitions map[hpaChunkPair][]hpaTransition
+}
+
+// NewHierarchicalPlanner partitions the map into chunks, and precomputes
+// the abstract graph.
+func NewHierarchicalPlanner(params *HierarchicalParams) (*HierarchicalPlanner, error) {
+	if params.Region == nil {
+		return nil, fmt.Errorf("hierarchical planning needs a region")
+	}
+	if params.ChunkSize <= 0 {
+		return nil, fmt.Errorf("invalid chunk size %d", params.ChunkSize)
+	}
+
+	h := &HierarchicalPlanner{
+		params:      params,
+		chunks:      map[ChunkID]*hpaChunk{},
+		transitions: map[hpaChunkPair][]hpaTransition{},
+	}
+
+	for _, p := range params.Region.ToOrderedList() {
+		id := h.ChunkOf(p)
+		chunk, ok := h.chunks[id]
+		if !ok {
+			chunk = &hpaChunk{cells: NewHexSet()}
+			h.chunks[id] = chunk
+		}
+		chunk.cells.Add(p)
+	}
+
+	ids := h.chunkIDs()
+	for _, id := range ids {
+		chunk := h.chunks[id]
+		neighbours := map[ChunkID]bool{}
+		for _, p := range chunk.cells.ToOrderedList() {
+			for _, nb := range p.Neighbours() {
+				if other := h.ChunkOf(nb); other != id && params.Region.Contains(nb) {
+					neighbours[other] = true
+				}
+			}
+		}
+		for other := range neighbours {
+			chunk.neighbours = append(chunk.neighbours, other)
+		}
+		sort.Slice(chunk.neighbours, func(i, j int) bool {
+			return chunk.neighbours[i].less(chunk.neighbours[j])
+		})
+	}
+
+	for _, id := range ids {
+		for _, other := range h.chunks[id].neighbours {
+			if id.less(other) {
+				h.connect(id, other)
+			}
+		}
+	}
+	for _, id := range ids {
+		h.buildEdges(id)
+	}
+
+	return h, nil
+}
+
+func (h *HierarchicalPlanner) chunkIDs() []ChunkID {
+	var rv []ChunkID
+	for id := range h.chunks {
+		rv = append(rv, id)
+	}
+	sort.Slice(rv, func(i, j int) bool { return rv[i].less(rv[j]) })
+	return rv
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// ChunkOf returns the chunk containing a hex.
+func (h *HierarchicalPlanner) ChunkOf(p HexCoord) ChunkID {
+	n := h.params.ChunkSize
+	if h.params.Shape == RectangularChunks {
+		return ChunkID{floorDiv(p.X, n), floorDiv(floorDiv(p.Y, 2), n)}
+	}
+
+	// Hexagons of radius n are centred on the lattice spanned by u and
+	// its rotation v; find the lattice point nearest p.
+	u := NewHex(n+1, 3*n+1)
+	v := u.Rotated(1)
+	det := float64(u.X*v.Y - u.Y*v.X)
+	i0 := int(math.Round(float64(p.X*v.Y-p.Y*v.X) / det))
+	j0 := int(math.Round(float64(p.Y*u.X-p.X*u.Y) / det))
+	for i := i0 - 1; i <= i0+1; i++ {
+		for j := j0 - 1; j <= j0+1; j++ {
+			centre := NewHex(i*u.X+j*v.X, i*u.Y+j*v.Y)
+			if p.StepsTo(centre) <= n {
+				return ChunkID{i, j}
+			}
+		}
+	}
+	panic(fmt.Sprintf("no chunk found for %v", p))
+}
+
+// localCost restricts the cost callback to a set of cells.
+func (h *HierarchicalPlanner) localCost(cells *HexSet) func(HexCoord, HexCoord) (float64, bool) {
+	return func(a, b HexCoord) (float64, bool) {
+		if !cells.Contains(b) {
+			return 0, false
+		}
+		return h.params.Cost(a, b)
+	}
+}
+
+// connect computes the transitions between two neighbouring chunks. The
+// crossings between them are grouped into stretches, where consecutive
+// crossings are linked on both sides, and one crossing in the middle of
+// each stretch becomes a transition.
+func (h *HierarchicalPlanner) connect(a, b ChunkID) {
+	ca, cb := h.chunks[a], h.chunks[b]
+	passable := func(p, q HexCoord) bool {
+		_, ok := h.params.Cost(p, q)
+		return ok
+	}
+	linked := func(p, q HexCoord) bool {
+		return p == q || (p.StepsTo(q) == 1 && passable(p, q) && passable(q, p))
+	}
+
+	var crossings []hpaTransition
+	for _, p := range ca.cells.ToOrderedList() {
+		for _, q := range p.Neighbours() {
+			if cb.cells.Contains(q) && (passable(p, q) || passable(q, p)) {
+				crossings = append(crossings, hpaTransition{from: p, to: q})
+			}
+		}
+	}
+
+	var transitions []hpaTransition
+	seen := make([]bool, len(crossings))
+	for i := range crossings {
+		if seen[i] {
+			continue
+		}
+		seen[i] = true
+		stretch := []hpaTransition{crossings[i]}
+		for k := 0; k < len(stretch); k++ {
+			for j, c := range crossings {
+				if !seen[j] && linked(stretch[k].from, c.from) && linked(stretch[k].to, c.to) {
+					seen[j] = true
+					stretch = append(stretch, c)
+				}
+			}
+		}
+		sort.Slice(stretch, func(x, y int) bool {
+			if stretch[x].from != stretch[y].from {
+				return stretch[x].from.Less(stretch[y].from)
+			}
+			return stretch[x].to.Less(stretch[y].to)
+		})
+
+		mid := stretch[len(stretch)/2]
+		if cost, ok := h.params.Cost(mid.from, mid.to); ok {
+			transitions = append(transitions, hpaTransition{mid.from, mid.to, cost})
+		}
+		if cost, ok := h.params.Cost(mid.to, mid.from); ok {
+			transitions = append(transitions, hpaTransition{mid.to, mid.from, cost})
+		}
+	}
+
+	h.transitions[newHPAChunkPair(a, b)] = transitions
+}
+
+// buildEdges collects the entrances of a chunk from its transitions, and
+// computes the cheapest paths between them within the chunk.
+func (h *HierarchicalPlanner) buildEdges(id ChunkID) {
+	chunk := h.chunks[id]
+	entrances := NewHexSet()
+	chunk.edges = map[HexCoord][]hpaEdge{}
+	chunk.exits = map[HexCoord][]hpaEdge{}
+
+	for _, other := range chunk.neighbours {
+		for _, t := range h.transitions[newHPAChunkPair(id, other)] {
+			if chunk.cells.Contains(t.from) {
+				entrances.Add(t.from)
+				chunk.exits[t.from] = append(chunk.exits[t.from], hpaEdge{t.to, t.cost})
+			} else {
+				entrances.Add(t.to)
+			}
+		}
+	}
+
+	cost := h.localCost(chunk.cells)
+	chunk.entrances = entrances.ToOrderedList()
+	for _, e := range chunk.entrances {
+		field := DistanceField(NewHexSetSingleton(e), cost, 0)
+		for _, f := range chunk.entrances {
+			if c, ok := field.Cost[f]; ok && f != e {
+				chunk.edges[e] = append(chunk.edges[e], hpaEdge{f, c})
+			}
+		}
+	}
+}
+
+// InvalidateChunk recomputes the part of the abstract graph around a chunk,
+// and must be called after the costs of stepping into or out of any of its
+// hexes change.
+func (h *HierarchicalPlanner) InvalidateChunk(id ChunkID) {
+	chunk, ok := h.chunks[id]
+	if !ok {
+		return
+	}
+	for _, other := range chunk.neighbours {
+		h.connect(id, other)
+	}
+	h.buildEdges(id)
+	for _, other := range chunk.neighbours {
+		h.buildEdges(other)
+	}
+}
+
+// FindPath finds a path between two hexes of the map, by searching the
+// abstract graph and refining the result. Errors are ErrNoPath, or an error
+// if either hex is outside the map.
+func (h *HierarchicalPlanner) FindPath(start, goal HexCoord) (*AStarResult, error) {
+	if !h.params.Region.Contains(start) || !h.params.Region.Contains(goal) {
+		return nil, fmt.Errorf("path endpoints %v and %v must be within the region", start, goal)
+	}
+	if start == goal {
+		return &AStarResult{Path: []HexCoord{start}}, nil
+	}
+
+	startID, goalID := h.ChunkOf(start), h.ChunkOf(goal)
+	startChunk, goalChunk := h.chunks[startID], h.chunks[goalID]
+
+	// Connect the endpoints to the entrances of their chunks.
+	fromStart := DistanceField(NewHexSetSingleton(start), h.localCost(startChunk.cells), 0).Cost
+	toGoal := DistanceField(NewHexSetSingleton(goal), func(a, b HexCoord) (float64, bool) {
+		if !goalChunk.cells.Contains(b) {
+			return 0, false
+		}
+		return h.params.Cost(b, a)
+	}, 0).Cost
+
+	var heuristic func(HexCoord) float64
+	if h.params.Heuristic != nil {
+		heuristic = func(p HexCoord) float64 { return h.params.Heuristic(p, goal) }
+	}
+
+	abstract, err := StateSearch(&StateSearchParams[HexCoord]{
+		Start:  []HexCoord{start},
+		IsGoal: func(p HexCoord) bool { return p == goal },
+		Successors: func(p HexCoord) []StateStep[HexCoord] {
+			var rv []StateStep[HexCoord]
+			add := func(to HexCoord, cost float64) {
+				rv = append(rv, StateStep[HexCoord]{State: to, Cost: cost})
+			}
+
+			chunk := h.chunks[h.ChunkOf(p)]
+			if p == start {
+				for _, e := range startChunk.entrances {
+					if c, ok := fromStart[e]; ok && e != start {
+						add(e, c)
+					}
+				}
+				if c, ok := fromStart[goal]; ok && startID == goalID {
+					add(goal, c)
+				}
+			} else {
+				for _, e := range chunk.edges[p] {
+					add(e.to, e.cost)
+				}
+				if c, ok := toGoal[p]; ok && chunk == goalChunk {
+					add(goal, c)
+				}
+			}
+			for _, e := range chunk.exits[p] {
+				add(e.to, e.cost)
+			}
+			return rv
+		},
+		Heuristic: heuristic,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rv := &AStarResult{Path: []HexCoord{start}}
+	for i := 1; i < len(abstract.Path); i++ {
+		from, to := abstract.Path[i-1], abstract.Path[i]
+		id := h.ChunkOf(from)
+		if id != h.ChunkOf(to) {
+			cost, _ := h.params.Cost(from, to)
+			rv.Cost += cost
+			rv.Path = append(rv.Path, to)
+			continue
+		}
+
+		var local func(HexCoord) float64
+		if h.params.Heuristic != nil {
+			local = func(p HexCoord) float64 { return h.params.Heuristic(p, to) }
+		}
+		segment, err := AStar(&AStarParams{
+			Start:     NewHexSetSingleton(from),
+			IsGoal:    func(p HexCoord) bool { return p == to },
+			Cost:      h.localCost(h.chunks[id].cells),
+			Heuristic: local,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("refining path from %v to %v: %w", from, to, err)
+		}
+		rv.Cost += segment.Cost
+		rv.Path = append(rv.Path, segment.Path[1:]...)
+	}
+
+	return rv, nil
+}
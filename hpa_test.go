@@ -0,0 +1,207 @@
+package hex
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestChunkOf(t *testing.T) {
+	for _, tc := range []struct {
+		shape ChunkShape
+		size  int
+		cells int
+	}{
+		{RectangularChunks, 4, 16},
+		{HexagonalChunks, 2, 19},
+		{HexagonalChunks, 5, 91},
+	} {
+		h, err := NewHierarchicalPlanner(&HierarchicalParams{
+			Region:    NewHexSet(),
+			Shape:     tc.shape,
+			ChunkSize: tc.size,
+		})
+		if err != nil {
+			t.Fatalf("NewHierarchicalPlanner failed: %v", err)
+		}
+
+		sizes := map[ChunkID]int{}
+		for _, p := range HexDisk(40) {
+			sizes[h.ChunkOf(p)]++
+		}
+		if got := sizes[h.ChunkOf(Origin)]; got != tc.cells {
+			t.Errorf("shape %v size %d: expected %d hexes in a chunk, got %d", tc.shape, tc.size, tc.cells, got)
+		}
+	}
+}
+
+func testHierarchicalMap(seed int64) (*HexSet, func(HexCoord, HexCoord) (float64, bool)) {
+	r := rand.New(rand.NewSource(seed))
+	region := NewHexSetAround(Origin, 24)
+	blocked := NewHexSet()
+	for _, p := range region.ToOrderedList() {
+		if r.Float64() < 0.25 {
+			blocked.Add(p)
+		}
+	}
+	return region, func(a, b HexCoord) (float64, bool) {
+		return 1, region.Contains(b) && !blocked.Contains(b) && !blocked.Contains(a)
+	}
+}
+
+func checkHierarchicalPath(t *testing.T, h *HierarchicalPlanner, cost func(HexCoord, HexCoord) (float64, bool), start, goal HexCoord) {
+	expected, expectedErr := AStar(&AStarParams{
+		Start:     NewHexSetSingleton(start),
+		IsGoal:    func(p HexCoord) bool { return p == goal },
+		Cost:      cost,
+		Heuristic: func(p HexCoord) float64 { return float64(p.StepsTo(goal)) },
+	})
+	result, err := h.FindPath(start, goal)
+	if (err != nil) != (expectedErr != nil) {
+		t.Fatalf("path from %v to %v: expected error %v, got %v", start, goal, expectedErr, err)
+	}
+	if err != nil {
+		return
+	}
+
+	path := result.Path
+	if path[0] != start || path[len(path)-1] != goal {
+		t.Fatalf("path does not go from %v to %v: %v", start, goal, path)
+	}
+	total := 0.0
+	for i := 1; i < len(path); i++ {
+		c, ok := cost(path[i-1], path[i])
+		if !ok || path[i].StepsTo(path[i-1]) != 1 {
+			t.Fatalf("illegal step from %v to %v", path[i-1], path[i])
+		}
+		total += c
+	}
+	if total != result.Cost {
+		t.Errorf("path costs %v, but reported cost is %v", total, result.Cost)
+	}
+	if result.Cost < expected.Cost || result.Cost > 1.2*expected.Cost+2 {
+		t.Errorf("path from %v to %v costs %v, but the cheapest costs %v", start, goal, result.Cost, expected.Cost)
+	}
+}
+
+func TestHierarchicalPlanner(t *testing.T) {
+	region, cost := testHierarchicalMap(1)
+	r := rand.New(rand.NewSource(2))
+
+	for _, shape := range []ChunkShape{RectangularChunks, HexagonalChunks} {
+		h, err := NewHierarchicalPlanner(&HierarchicalParams{
+			Region:    region,
+			Cost:      cost,
+			Heuristic: func(a, b HexCoord) float64 { return float64(a.StepsTo(b)) },
+			Shape:     shape,
+			ChunkSize: 5,
+		})
+		if err != nil {
+			t.Fatalf("NewHierarchicalPlanner failed: %v", err)
+		}
+		for i := 0; i < 50; i++ {
+			start, _ := region.RandomFrom(r)
+			goal, _ := region.RandomFrom(r)
+			checkHierarchicalPath(t, h, cost, start, goal)
+		}
+
+		if _, err := h.FindPath(Origin, NewHex(0, 100)); err == nil {
+			t.Errorf("expected an error for a goal outside the region")
+		}
+	}
+}
+
+func TestHierarchicalPlannerInvalidateChunk(t *testing.T) {
+	region := NewHexSetAround(Origin, 20)
+	wall := NewHexSet()
+	cost := func(a, b HexCoord) (float64, bool) {
+		return 1, region.Contains(b) && !wall.Contains(b)
+	}
+	h, err := NewHierarchicalPlanner(&HierarchicalParams{
+		Region:    region,
+		Cost:      cost,
+		Shape:     HexagonalChunks,
+		ChunkSize: 4,
+	})
+	if err != nil {
+		t.Fatalf("NewHierarchicalPlanner failed: %v", err)
+	}
+
+	start, goal := NewHex(-10, 0), NewHex(10, 0)
+	checkHierarchicalPath(t, h, cost, start, goal)
+
+	// Build a wall across the map, with a single gap, and tell the planner
+	// about the chunks it passes through.
+	changed := map[ChunkID]bool{}
+	for y := -40; y <= 40; y += 2 {
+		if y != 30 {
+			p := NewHex(0, y)
+			wall.Add(p)
+			changed[h.ChunkOf(p)] = true
+		}
+	}
+	for id := range changed {
+		h.InvalidateChunk(id)
+	}
+
+	result, err := h.FindPath(start, goal)
+	if err != nil {
+		t.Fatalf("FindPath failed after building the wall: %v", err)
+	}
+	for _, p := range result.Path {
+		if wall.Contains(p) {
+			t.Fatalf("path goes through the wall at %v", p)
+		}
+	}
+	checkHierarchicalPath(t, h, cost, start, goal)
+
+	// Close the gap.
+	gap := NewHex(0, 30)
+	wall.Add(gap)
+	h.InvalidateChunk(h.ChunkOf(gap))
+	if _, err := h.FindPath(start, goal); err != ErrNoPath {
+		t.Errorf("expected ErrNoPath once the wall is closed, got %v", err)
+	}
+}
+
+func BenchmarkHierarchicalPlanner(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	region, blocked := NewHexSet(), NewHexSet()
+	for _, p := range HexDisk(250) {
+		region.Add(p)
+		if r.Float64() < 0.1 {
+			blocked.Add(p)
+		}
+	}
+	cost := func(a, c HexCoord) (float64, bool) { return 1, region.Contains(c) && !blocked.Contains(c) }
+	heuristic := func(a, c HexCoord) float64 { return float64(a.StepsTo(c)) }
+	start, goal := NewHex(-250, 0), NewHex(250, 0)
+	blocked.Remove(start)
+	blocked.Remove(goal)
+
+	h, err := NewHierarchicalPlanner(&HierarchicalParams{
+		Region:    region,
+		Cost:      cost,
+		Heuristic: heuristic,
+		Shape:     HexagonalChunks,
+		ChunkSize: 8,
+	})
+	if err != nil {
+		b.Fatalf("NewHierarchicalPlanner failed: %v", err)
+	}
+
+	b.Run("AStar", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			AStar(&AStarParams{
+				Start:     NewHexSetSingleton(start),
+				IsGoal:    func(p HexCoord) bool { return p == goal },
+				Cost:      cost,
+				Heuristic: func(p HexCoord) float64 { return heuristic(p, goal) },
+			})
+		}
+	})
+	b.Run("Hierarchical", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			h.FindPath(start, goal)
+		}
+	})
+}
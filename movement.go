@@ -0,0 +1,163 @@
+package hex
+
+import (
+	"fmt"
+)
+
+// MovementRangeParams provides parameters for finding the hexes a unit can
+// move to. Cost gives the movement points spent stepping from one hex to
+// another, as for AStarParams. If CanStop is set, hexes for which it returns
+// false may be moved through but not ended on (e.g. hexes occupied by allies).
+// If ZOC is set, movement must obey its zone-of-control rules.
+type MovementRangeParams struct {
+	Start          HexCoord
+	MovementPoints float64
+	Cost           func(HexCoord, HexCoord) (float64, bool)
+	CanStop        func(HexCoord) bool
+	ZOC            *ZoneOfControl
+}
+
+// MovementRange represents the hexes a unit can end its movement on, along
+// with the movement points it has remaining there.
+type MovementRange struct {
+	Start     HexCoord
+	Remaining HexMap[float64]
+
+	end   map[HexCoord]movementState
+	trail map[movementState]movementState
+}
+
+// movementState is a hex along with the direction of the step that entered
+// it, so that turns can be counted.
+type movementState struct {
+	point HexCoord
+	dir   HexDir
+	moved bool
+}
+
+type movementNode struct {
+	state  movementState
+	parent movementState
+	spent  float64
+	turns  int
+	seq    int
+}
+
+// Reachable finds the hexes a unit with a number of movement points can
+// move to from a starting hex.
+func Reachable(start HexCoord, movementPoints float64, cost func(HexCoord, HexCoord) (float64, bool)) *MovementRange {
+	return FindMovementRange(&MovementRangeParams{
+		Start:          start,
+		MovementPoints: movementPoints,
+		Cost:           cost,
+	})
+}
+
+// FindMovementRange finds the hexes a unit can move to. Among equally cheap
+// paths to a hex, the one with the fewest turns is chosen.
+func FindMovementRange(params *MovementRangeParams) *MovementRange {
+	rv := &MovementRange{
+		Start:     params.Start,
+		Remaining: HexMap[float64]{},
+		end:       map[HexCoord]movementState{},
+		trail:     map[movementState]movementState{},
+	}
+
+	q := newPriorityQueue(func(a, b movementNode) bool {
+		if a.spent != b.spent {
+			return a.spent < b.spent
+		}
+		if a.turns != b.turns {
+			return a.turns < b.turns
+		}
+		return a.seq < b.seq
+	})
+	seq := 0
+	push := func(n movementNode) {
+		n.seq = seq
+		seq++
+		q.Push(n)
+	}
+
+	settled := map[movementState]bool{}
+	push(movementNode{state: movementState{point: params.Start}})
+
+	for q.Len() > 0 {
+		current := q.Pop()
+		if settled[current.state] {
+			continue
+		}
+		settled[current.state] = true
+		if current.state.moved {
+			rv.trail[current.state] = current.parent
+		}
+
+		p := current.state.point
+		if _, done := rv.end[p]; !done {
+			if p == params.Start || params.CanStop == nil || params.CanStop(p) {
+				rv.end[p] = current.state
+				rv.Remaining[p] = params.MovementPoints - current.spent
+			}
+		}
+
+		if !params.ZOC.CanLeave(p, !current.state.moved) {
+			continue
+		}
+
+		for _, d := range OrderedDirections {
+			next := movementState{
+				point: p.AddDelta(Directions[d]),
+				dir:   d,
+				moved: true,
+			}
+			if settled[next] {
+				continue
+			}
+			stepCost, ok := params.Cost(p, next.point)
+			if !ok {
+				continue
+			}
+			zocCost, ok := params.ZOC.StepCost(p, next.point)
+			stepCost += zocCost
+			if !ok || current.spent+stepCost > params.MovementPoints {
+				continue
+			}
+			turns := current.turns
+			if current.state.moved && current.state.dir != d {
+				turns++
+			}
+			push(movementNode{
+				state:  next,
+				parent: current.state,
+				spent:  current.spent + stepCost,
+				turns:  turns,
+			})
+		}
+	}
+
+	return rv
+}
+
+// PathTo computes the path a unit takes to move to a hex in the
+// MovementRange, starting with the start hex and ending with the hex.
+func (m *MovementRange) PathTo(p HexCoord) ([]HexCoord, error) {
+	state, ok := m.end[p]
+	if !ok {
+		return nil, fmt.Errorf("%v is not in movement range", p)
+	}
+
+	var rpath []HexCoord
+	for {
+		rpath = append(rpath, state.point)
+		if !state.moved {
+			break
+		}
+		state = m.trail[state]
+	}
+
+	path := make([]HexCoord, len(rpath))
+	for i, q := range rpath {
+		path[len(rpath)-1-i] = q
+	}
+	return path, nil
+}
@@ -0,0 +1,97 @@
+package hex
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReachable(t *testing.T) {
+	rough := NewHex(-1, 1)
+	cost := func(a, b HexCoord) (float64, bool) {
+		if b == rough {
+			return 3, true
+		}
+		return 1, true
+	}
+
+	m := Reachable(Origin, 3, cost)
+
+	// (-3,3) can only be reached in three steps through the rough hex.
+	if got, expect := len(m.Remaining), len(HexDisk(3))-1; got != expect {
+		t.Errorf("expected %d reachable hexes, got %d", expect, got)
+	}
+	if m.Remaining[Origin] != 3 || m.Remaining[rough] != 0 || m.Remaining[NewHex(0, 4)] != 1 {
+		t.Errorf("wrong remaining movement points: %v", m.Remaining)
+	}
+	if _, ok := m.Remaining[NewHex(0, 8)]; ok {
+		t.Errorf("expected (0,8) to be out of range")
+	}
+
+	for p, remaining := range m.Remaining {
+		path, err := m.PathTo(p)
+		if err != nil {
+			t.Fatalf("PathTo(%v) failed: %v", p, err)
+		}
+		if path[0] != Origin || path[len(path)-1] != p {
+			t.Errorf("bad path to %v: %v", p, path)
+		}
+		spent := 0.0
+		for i := 1; i < len(path); i++ {
+			c, _ := cost(path[i-1], path[i])
+			spent += c
+		}
+		if spent != 3-remaining {
+			t.Errorf("path %v to %v spends %v, expected %v", path, p, spent, 3-remaining)
+		}
+	}
+
+	if _, err := m.PathTo(NewHex(0, 8)); err == nil {
+		t.Errorf("expected error for path out of range")
+	}
+}
+
+func TestReachablePrefersStraightPaths(t *testing.T) {
+	m := Reachable(Origin, 5, func(a, b HexCoord) (float64, bool) { return 1, true })
+
+	path, _ := m.PathTo(NewHex(4, 4))
+	expect := []HexCoord{Origin, NewHex(1, 1), NewHex(2, 2), NewHex(3, 3), NewHex(4, 4)}
+	if !reflect.DeepEqual(path, expect) {
+		t.Errorf("expected straight path %v, got %v", expect, path)
+	}
+
+	path, _ = m.PathTo(NewHex(2, 6))
+	turns := 0
+	for i := 2; i < len(path); i++ {
+		if path[i].Minus(path[i-1]) != path[i-1].Minus(path[i-2]) {
+			turns++
+		}
+	}
+	if len(path) != 5 || turns != 1 {
+		t.Errorf("expected path with one turn, got %v", path)
+	}
+}
+
+func TestReachablePassThroughAllies(t *testing.T) {
+	ally := NewHex(0, 2)
+	enemy := NewHex(1, 1)
+	m := FindMovementRange(&MovementRangeParams{
+		Start:          Origin,
+		MovementPoints: 2,
+		Cost: func(a, b HexCoord) (float64, bool) {
+			return 1, b != enemy
+		},
+		CanStop: func(p HexCoord) bool { return p != ally },
+	})
+
+	if _, ok := m.Remaining[ally]; ok {
+		t.Errorf("expected not to be able to stop on ally")
+	}
+	if _, ok := m.Remaining[enemy]; ok {
+		t.Errorf("expected not to be able to move onto enemy")
+	}
+
+	path, err := m.PathTo(NewHex(0, 4))
+	if err != nil || !reflect.DeepEqual(path, []HexCoord{Origin, ally, NewHex(0, 4)}) {
+		t.Errorf("expected to move through ally, got %v (%v)", path, err)
+	}
+}
@@ -0,0 +1,104 @@
+package hex
+
+// MultiGoalParams provides parameters for a search for paths to several
+// goals at once. Cost and MaxCost are as for AStarParams. If K is nonzero,
+// the search stops once paths to the K cheapest goals have been found;
+// otherwise it finds paths to every reachable goal.
+type MultiGoalParams struct {
+	Start   *HexSet
+	Goals   *HexSet
+	Cost    func(HexCoord, HexCoord) (float64, bool)
+	K       int
+	MaxCost float64
+	Limits  SearchLimits
+	Stats   *SearchStats
+}
+
+type multiGoalNode struct {
+	point  HexCoord
+	cost   float64
+	parent *multiGoalNode
+	seq    int
+}
+
+// FindPathsToGoals finds cheapest paths to several goals in a single
+// search, in order of increasing cost. If fewer goals than requested are
+// reachable, the paths that were found are returned; ErrNoPath (or
+// ErrCostLimit) is returned only if no goal is reachable.
+func FindPathsToGoals(params *MultiGoalParams) ([]*AStarResult, error) {
+	monitor := newSearchMonitor(params.Limits, params.Stats)
+	defer monitor.finish()
+
+	want := params.Goals.Size()
+	if params.K != 0 && params.K < want {
+		want = params.K
+	}
+
+	q := newPriorityQueue(func(a, b *multiGoalNode) bool {
+		if a.cost != b.cost {
+			return a.cost < b.cost
+		}
+		return a.seq < b.seq
+	})
+	seq := 0
+	push := func(n *multiGoalNode) {
+		n.seq = seq
+		seq++
+		q.Push(n)
+	}
+	for _, p := range params.Start.ToOrderedList() {
+		push(&multiGoalNode{point: p})
+	}
+
+	var rv []*AStarResult
+	failure := ErrNoPath
+	closed := map[HexCoord]bool{}
+	for q.Len() > 0 && len(rv) < want {
+		if err := monitor.expand(q.Len()); err != nil {
+			return rv, err
+		}
+
+		current := q.Pop()
+		if closed[current.point] {
+			continue
+		}
+		closed[current.point] = true
+
+		if params.MaxCost != 0 && current.cost > params.MaxCost {
+			failure = ErrCostLimit
+			break
+		}
+
+		if params.Goals.Contains(current.point) {
+			var rpath []HexCoord
+			for n := current; n != nil; n = n.parent {
+				rpath = append(rpath, n.point)
+			}
+			result := &AStarResult{Cost: current.cost}
+			for i := len(rpath) - 1; i >= 0; i-- {
+				result.Path = append(result.Path, rpath[i])
+			}
+			rv = append(rv, result)
+		}
+
+		for _, nb := range current.point.Neighbours() {
+			if closed[nb] {
+				continue
+			}
+			stepCost, ok := params.Cost(current.point, nb)
+			if !ok {
+				continue
+			}
+			push(&multiGoalNode{
+				point:  nb,
+				cost:   current.cost + stepCost,
+				parent: current,
+			})
+		}
+	}
+
+	if len(rv) == 0 && want > 0 {
+		return nil, failure
+	}
+	return rv, nil
+}
@@ -0,0 +1,45 @@
+package hex
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFindPathsToGoals(t *testing.T) {
+	goals := NewHexSet()
+	goals.AddHex(0, 6)
+	goals.AddHex(2, 0)
+	goals.AddHex(-5, -5)
+	goals.AddHex(0, 40)
+	region := NewHexSetAround(Origin, 10)
+	params := &MultiGoalParams{
+		Start: NewHexSetSingleton(Origin),
+		Goals: goals,
+		Cost:  func(a, b HexCoord) (float64, bool) { return 1, region.Contains(b) },
+	}
+
+	results, err := FindPathsToGoals(params)
+	if err != nil {
+		t.Fatalf("FindPathsToGoals failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected paths to the 3 reachable goals, got %d", len(results))
+	}
+	for i, expect := range []HexCoord{NewHex(2, 0), NewHex(0, 6), NewHex(-5, -5)} {
+		path := results[i].Path
+		if path[len(path)-1] != expect || results[i].Cost != float64(expect.StepsTo(Origin)) {
+			t.Errorf("expected result %d to reach %v, got %+v", i, expect, results[i])
+		}
+	}
+
+	params.K = 2
+	results, _ = FindPathsToGoals(params)
+	if len(results) != 2 {
+		t.Errorf("expected 2 closest goals, got %d", len(results))
+	}
+
+	params.Goals = NewHexSetSingleton(NewHex(0, 40))
+	if _, err := FindPathsToGoals(params); !errors.Is(err, ErrNoPath) {
+		t.Errorf("expected ErrNoPath, got %v", err)
+	}
+}
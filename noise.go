@@ -0,0 +1,137 @@
+package hex
+
+import "math"
+
+// The noise functions below are deterministic given a seed, and are careful
+// to give bit-identical results on all platforms: every product that is
+// added to something is explicitly converted to float64, which prevents the
+// compiler from fusing the operations into FMA instructions on architectures
+// that have them.
+
+func noiseMix(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+func noiseHash(seed int64, x, y int64) uint64 {
+	h := noiseMix(uint64(seed) + 0x9e3779b97f4a7c15)
+	h = noiseMix(h ^ uint64(x))
+	return noiseMix(h ^ uint64(y))
+}
+
+// noiseFade is the quintic smoothstep 6t^5 - 15t^4 + 10t^3.
+func noiseFade(t float64) float64 {
+	return float64(t*t*t) * (float64(t*(float64(t*6)-15)) + 10)
+}
+
+func noiseLerp(a, b, t float64) float64 {
+	return a + float64(t*(b-a))
+}
+
+var noiseGradients = [8]GeoCoord{
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+	{math.Sqrt2 / 2, math.Sqrt2 / 2}, {-math.Sqrt2 / 2, math.Sqrt2 / 2},
+	{math.Sqrt2 / 2, -math.Sqrt2 / 2}, {-math.Sqrt2 / 2, -math.Sqrt2 / 2},
+}
+
+// ValueNoise computes smooth value noise at a point on the plane, in the
+// range [-1, 1]. Random values are placed on a square lattice of unit spacing
+// and interpolated between.
+func ValueNoise(seed int64, p GeoCoord) float64 {
+	fx, fy := math.Floor(p.X), math.Floor(p.Y)
+	x0, y0 := int64(fx), int64(fy)
+	u, v := noiseFade(p.X-fx), noiseFade(p.Y-fy)
+
+	value := func(x, y int64) float64 {
+		return float64(noiseHash(seed, x, y)>>11)/(1<<52) - 1
+	}
+
+	a := noiseLerp(value(x0, y0), value(x0+1, y0), u)
+	b := noiseLerp(value(x0, y0+1), value(x0+1, y0+1), u)
+	return noiseLerp(a, b, v)
+}
+
+// GradientNoise computes smooth gradient (Perlin-style) noise at a point on
+// the plane, in the range [-1, 1]. Random gradients are placed on a square
+// lattice of unit spacing; the noise is zero at the lattice points.
+func GradientNoise(seed int64, p GeoCoord) float64 {
+	fx, fy := math.Floor(p.X), math.Floor(p.Y)
+	x0, y0 := int64(fx), int64(fy)
+	dx, dy := p.X-fx, p.Y-fy
+	u, v := noiseFade(dx), noiseFade(dy)
+
+	dot := func(x, y int64, ox, oy float64) float64 {
+		g := noiseGradients[noiseHash(seed, x, y)&7]
+		return float64(g.X*ox) + float64(g.Y*oy)
+	}
+
+	a := noiseLerp(dot(x0, y0, dx, dy), dot(x0+1, y0, dx-1, dy), u)
+	b := noiseLerp(dot(x0, y0+1, dx, dy-1), dot(x0+1, y0+1, dx-1, dy-1), u)
+	rv := noiseLerp(a, b, v) * math.Sqrt2
+	return math.Max(-1, math.Min(1, rv))
+}
+
+// FractalNoise sums several octaves of noise at increasing frequencies and
+// decreasing amplitudes. Zero values for the fields mean: one octave, a
+// frequency of 1 (lattice cells per unit of geometric distance), a
+// lacunarity (frequency multiplier per octave) of 2, and a gain (amplitude
+// multiplier per octave) of 0.5.
+type FractalNoise struct {
+	Seed       int64
+	Octaves    int
+	Frequency  float64
+	Lacunarity float64
+	Gain       float64
+	// Gradient selects GradientNoise rather than ValueNoise for each octave.
+	Gradient bool
+}
+
+// At computes the noise at a point on the plane, in the range [-1, 1].
+func (n *FractalNoise) At(p GeoCoord) float64 {
+	octaves, freq, lacunarity, gain := n.Octaves, n.Frequency, n.Lacunarity, n.Gain
+	if octaves <= 0 {
+		octaves = 1
+	}
+	if freq == 0 {
+		freq = 1
+	}
+	if lacunarity == 0 {
+		lacunarity = 2
+	}
+	if gain == 0 {
+		gain = 0.5
+	}
+
+	noise := ValueNoise
+	if n.Gradient {
+		noise = GradientNoise
+	}
+
+	total, norm, amp := 0.0, 0.0, 1.0
+	for i := 0; i < octaves; i++ {
+		seed := n.Seed + int64(i)*0x5851f42d
+		total += float64(amp * noise(seed, p.Scaled(freq)))
+		norm += amp
+		freq *= lacunarity
+		amp *= gain
+	}
+
+	return total / norm
+}
+
+// AtHex computes the noise at the centre of a hex.
+func (n *FractalNoise) AtHex(p HexCoord) float64 {
+	return n.At(p.Geo())
+}
+
+// ThresholdField computes the HexSet of hexes in a region at which a field
+// (such as FractalNoise.AtHex) is at least a threshold.
+func ThresholdField(region *HexSet, field func(HexCoord) float64, threshold float64) *HexSet {
+	return region.Filtered(func(p HexCoord) bool {
+		return field(p) >= threshold
+	})
+}
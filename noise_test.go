@@ -0,0 +1,74 @@
+package hex
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNoiseRangeAndDeterminism(t *testing.T) {
+	for _, gradient := range []bool{false, true} {
+		n := &FractalNoise{Seed: 99, Octaves: 4, Frequency: 0.1, Gradient: gradient}
+		m := &FractalNoise{Seed: 100, Octaves: 4, Frequency: 0.1, Gradient: gradient}
+
+		differs := false
+		for _, p := range HexDisk(20) {
+			v := n.AtHex(p)
+			if v < -1 || v > 1 || math.IsNaN(v) {
+				t.Errorf("noise at %v out of range: %v", p, v)
+			}
+			if v != n.AtHex(p) {
+				t.Errorf("noise at %v is not deterministic", p)
+			}
+			if v != m.AtHex(p) {
+				differs = true
+			}
+		}
+		if !differs {
+			t.Errorf("expected different seeds to give different noise")
+		}
+	}
+}
+
+func TestNoiseIsSmooth(t *testing.T) {
+	eps := 1e-4
+	for _, noise := range []func(int64, GeoCoord) float64{ValueNoise, GradientNoise} {
+		for i := 0; i < 100; i++ {
+			p := GeoCoord{float64(i) * 0.37, float64(i) * -0.91}
+			q := p.Add(GeoCoord{eps, eps})
+			if d := math.Abs(noise(3, p) - noise(3, q)); d > 10*eps {
+				t.Errorf("noise changed by %v between %v and %v", d, p, q)
+			}
+		}
+	}
+
+	if v := GradientNoise(3, GeoCoord{5, -2}); v != 0 {
+		t.Errorf("expected gradient noise to be zero at lattice points, got %v", v)
+	}
+}
+
+func TestNoiseIsStable(t *testing.T) {
+	// These values must not change between platforms or releases, since
+	// users rely on seeds to reproduce maps.
+	p := GeoCoord{1.25, -3.5}
+	if got, expect := ValueNoise(1, p), -0.3917688012884664; got != expect {
+		t.Errorf("ValueNoise(1, %v) = %v, expected %v", p, got, expect)
+	}
+	if got, expect := GradientNoise(1, p), -0.1303785797707028; got != expect {
+		t.Errorf("GradientNoise(1, %v) = %v, expected %v", p, got, expect)
+	}
+}
+
+func TestThresholdField(t *testing.T) {
+	region := NewHexSetAround(Origin, 10)
+	n := &FractalNoise{Seed: 5, Octaves: 3, Frequency: 0.2}
+
+	s := ThresholdField(region, n.AtHex, 0.1)
+	if !region.ContainsSet(s) {
+		t.Errorf("thresholded set extends outside region")
+	}
+	for _, p := range region.Enumerate() {
+		if s.Contains(p) != (n.AtHex(p) >= 0.1) {
+			t.Errorf("wrong membership for %v with noise %v", p, n.AtHex(p))
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package hex
+
+import (
+	"math"
+	"math/rand"
+)
+
+// PoissonDiskParams provides parameters for Poisson-disk sampling. Chosen
+// hexes are at least MinDistance apart, measured in steps or (if Euclidean
+// is set) as the straight-line distance between hex centres. Both are in
+// units of hex steps, so the centres of adjacent hexes are 1 apart (half
+// their distance in GeoCoord units). A MaxCount of zero means no limit.
+type PoissonDiskParams struct {
+	Candidates  *HexSet
+	MinDistance float64
+	Euclidean   bool
+	MaxCount    int
+}
+
+// PoissonDiskSample picks hexes from a set of candidates such that no two
+// are closer than a minimum distance, by considering the candidates in random
+// order and keeping each one that is far enough from all those kept so far.
+// Unless MaxCount is reached, the result is maximal: every candidate is too
+// close to some chosen hex. The result is reproducible given a seed.
+func PoissonDiskSample(r *rand.Rand, params *PoissonDiskParams) []HexCoord {
+	tooClose := func(p HexCoord) bool {
+		if params.Euclidean {
+			// Computed exactly, since squared GeoCoord distances between
+			// centres are integers, and twice the distance in steps.
+			return float64(3*p.X*p.X+p.Y*p.Y) < 4*params.MinDistance*params.MinDistance
+		}
+		return float64(p.Radius()) < params.MinDistance
+	}
+
+	// Any two hexes k steps apart have centres at least k*sqrt(3)/2 apart.
+	reach := int(math.Ceil(params.MinDistance))
+	if params.Euclidean {
+		reach = int(math.Ceil(2 * params.MinDistance / sqrt3))
+	}
+
+	var exclusion []HexCoord
+	for _, p := range HexDisk(reach) {
+		if tooClose(p) {
+			exclusion = append(exclusion, p)
+		}
+	}
+
+	candidates := params.Candidates.ToOrderedList()
+	r.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	var rv []HexCoord
+	blocked := map[HexCoord]bool{}
+
+	for _, p := range candidates {
+		if params.MaxCount > 0 && len(rv) >= params.MaxCount {
+			break
+		}
+		if blocked[p] {
+			continue
+		}
+
+		rv = append(rv, p)
+		for _, d := range exclusion {
+			blocked[p.AddDelta(d)] = true
+		}
+	}
+
+	return rv
+}
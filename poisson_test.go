@@ -0,0 +1,89 @@
+package hex
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestPoissonDiskSample(t *testing.T) {
+	candidates := NewHexSetAround(Origin, 12)
+
+	for _, euclidean := range []bool{false, true} {
+		params := &PoissonDiskParams{
+			Candidates:  candidates,
+			MinDistance: 4,
+			Euclidean:   euclidean,
+		}
+		distance := func(a, b HexCoord) float64 {
+			if euclidean {
+				d := a.Minus(b)
+				return math.Sqrt(float64(3*d.X*d.X+d.Y*d.Y)) / 2
+			}
+			return float64(a.StepsTo(b))
+		}
+
+		sample := PoissonDiskSample(rand.New(rand.NewSource(3)), params)
+		if len(sample) < 2 {
+			t.Fatalf("expected several samples, got %v", sample)
+		}
+
+		for i, a := range sample {
+			for _, b := range sample[:i] {
+				if d := distance(a, b); d < params.MinDistance {
+					t.Errorf("samples %v and %v are only %v apart", a, b, d)
+				}
+			}
+		}
+
+		for _, p := range candidates.Enumerate() {
+			covered := false
+			for _, s := range sample {
+				if distance(p, s) < params.MinDistance {
+					covered = true
+				}
+			}
+			if !covered {
+				t.Errorf("sample is not maximal: %v could have been added", p)
+			}
+		}
+
+		again := PoissonDiskSample(rand.New(rand.NewSource(3)), params)
+		if !reflect.DeepEqual(sample, again) {
+			t.Errorf("same seed gave different samples")
+		}
+
+		params.MaxCount = 3
+		if limited := PoissonDiskSample(rand.New(rand.NewSource(3)), params); len(limited) != 3 {
+			t.Errorf("expected 3 samples with MaxCount, got %v", limited)
+		}
+	}
+}
+
+func TestPoissonDiskSampleEuclideanUnits(t *testing.T) {
+	candidates := NewHexSetAround(Origin, 3)
+
+	// Adjacent hexes are exactly one step apart, so every hex fits.
+	all := PoissonDiskSample(rand.New(rand.NewSource(1)), &PoissonDiskParams{
+		Candidates:  candidates,
+		MinDistance: 1,
+		Euclidean:   true,
+	})
+	if len(all) != candidates.Size() {
+		t.Errorf("expected all %d hexes with a minimum distance of 1, got %d", candidates.Size(), len(all))
+	}
+
+	spaced := PoissonDiskSample(rand.New(rand.NewSource(1)), &PoissonDiskParams{
+		Candidates:  candidates,
+		MinDistance: 1.5,
+		Euclidean:   true,
+	})
+	for i, a := range spaced {
+		for _, b := range spaced[:i] {
+			if a.StepsTo(b) == 1 {
+				t.Errorf("adjacent hexes %v and %v chosen with a minimum distance of 1.5", a, b)
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package hex
+
+// priorityQueue is a binary min-heap of items ordered by a less function.
+// If setIndex is set, it is called whenever an item moves within the heap
+// (with -1 when it is popped), which allows the item to be found and its
+// priority changed with Fix.
+type priorityQueue[T any] struct {
+	items    []T
+	less     func(a, b T) bool
+	setIndex func(x T, i int)
+}
+
+func newPriorityQueue[T any](less func(a, b T) bool) *priorityQueue[T] {
+	return &priorityQueue[T]{less: less}
+}
+
+func newIndexedPriorityQueue[T any](less func(a, b T) bool, setIndex func(x T, i int)) *priorityQueue[T] {
+	return &priorityQueue[T]{less: less, setIndex: setIndex}
+}
+
+func (q *priorityQueue[T]) Len() int {
+	return len(q.items)
+}
+
+func (q *priorityQueue[T]) place(x T, i int) {
+	q.items[i] = x
+	if q.setIndex != nil {
+		q.setIndex(x, i)
+	}
+}
+
+func (q *priorityQueue[T]) swap(i, j int) {
+	a, b := q.items[i], q.items[j]
+	q.place(b, i)
+	q.place(a, j)
+}
+
+func (q *priorityQueue[T]) up(i int) bool {
+	moved := false
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !q.less(q.items[i], q.items[parent]) {
+			break
+		}
+		q.swap(i, parent)
+		i = parent
+		moved = true
+	}
+	return moved
+}
+
+func (q *priorityQueue[T]) down(i int) {
+	for {
+		smallest := i
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child < len(q.items) && q.less(q.items[child], q.items[smallest]) {
+				smallest = child
+			}
+		}
+		if smallest == i {
+			break
+		}
+		q.swap(i, smallest)
+		i = smallest
+	}
+}
+
+func (q *priorityQueue[T]) Push(x T) {
+	q.items = append(q.items, x)
+	q.place(x, len(q.items)-1)
+	q.up(len(q.items) - 1)
+}
+
+func (q *priorityQueue[T]) Peek() T {
+	return q.items[0]
+}
+
+func (q *priorityQueue[T]) Pop() T {
+	rv := q.items[0]
+	last := len(q.items) - 1
+	if last > 0 {
+		q.place(q.items[last], 0)
+	}
+	q.items = q.items[:last]
+	q.down(0)
+
+	if q.setIndex != nil {
+		q.setIndex(rv, -1)
+	}
+	return rv
+}
+
+// Remove removes the item at index i.
+func (q *priorityQueue[T]) Remove(i int) T {
+	rv := q.items[i]
+	last := len(q.items) - 1
+	if i != last {
+		q.place(q.items[last], i)
+	}
+	q.items = q.items[:last]
+	if i != last {
+		q.Fix(i)
+	}
+
+	if q.setIndex != nil {
+		q.setIndex(rv, -1)
+	}
+	return rv
+}
+
+// Fix restores the heap order after the priority of the item at index i
+// has changed.
+func (q *priorityQueue[T]) Fix(i int) {
+	if !q.up(i) {
+		q.down(i)
+	}
+}
@@ -0,0 +1,161 @@
+package hex
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// RandomWalkBlob generates a blob of a given size by a random walk from a
+// start hex, never stepping outside a boundary HexSet. The result is
+// reproducible given a seed, and always connected.
+func RandomWalkBlob(r *rand.Rand, boundary *HexSet, start HexCoord, size int) (*HexSet, error) {
+	if !boundary.Contains(start) {
+		return nil, fmt.Errorf("start %v is outside boundary", start)
+	}
+	if size <= 0 {
+		return NewHexSet(), nil
+	}
+	if reachable, _ := FindConnectedComponent(start, boundary.Predicate()); reachable.Size() < size {
+		return nil, fmt.Errorf("blob of size %d does not fit in boundary (only %d hexes reachable)", size, reachable.Size())
+	}
+
+	rv := NewHexSetSingleton(start)
+	pos := start
+
+	for rv.Size() < size {
+		next := pos.AddDelta(Directions[OrderedDirections[r.Intn(6)]])
+		if !boundary.Contains(next) {
+			continue
+		}
+		pos = next
+		rv.Add(pos)
+	}
+
+	return rv, nil
+}
+
+// RandomConnectedRegion generates a connected region of a given size within
+// a boundary HexSet, by growing outwards from a start hex one random
+// neighbouring hex at a time. The result is reproducible given a seed.
+func RandomConnectedRegion(r *rand.Rand, boundary *HexSet, start HexCoord, size int) (*HexSet, error) {
+	if !boundary.Contains(start) {
+		return nil, fmt.Errorf("start %v is outside boundary", start)
+	}
+
+	rv := NewHexSet()
+	frontier := []HexCoord{start}
+	seen := map[HexCoord]bool{start: true}
+
+	for rv.Size() < size {
+		if len(frontier) == 0 {
+			return nil, fmt.Errorf("region of size %d does not fit in boundary (only %d hexes reachable)", size, rv.Size())
+		}
+
+		i := r.Intn(len(frontier))
+		p := frontier[i]
+		frontier[i] = frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+
+		rv.Add(p)
+
+		for _, nb := range p.Neighbours() {
+			if !seen[nb] && boundary.Contains(nb) {
+				seen[nb] = true
+				frontier = append(frontier, nb)
+			}
+		}
+	}
+
+	return rv, nil
+}
+
+// CaveParams provides parameters for generating caves with a cellular
+// automaton. Each generation, a hex not in the set is added if its number of
+// neighbours in the set is listed in Birth, and a hex in the set is kept if
+// its number of neighbours in the set is listed in Survival.
+type CaveParams struct {
+	Boundary        *HexSet
+	FillProbability float64
+	Birth           []int
+	Survival        []int
+	Iterations      int
+	// OutsideIsAlive makes hexes outside the boundary count as being in the
+	// set when counting neighbours.
+	OutsideIsAlive bool
+	// Connected keeps only the largest connected component of the result.
+	Connected bool
+}
+
+// GenerateCave generates a HexSet within a boundary using a cellular
+// automaton, starting from a random fill. The result is reproducible given a
+// seed.
+func GenerateCave(r *rand.Rand, params *CaveParams) *HexSet {
+	cells := params.Boundary.ToOrderedList()
+
+	var birth, survival [7]bool
+	for _, n := range params.Birth {
+		if n >= 0 && n <= 6 {
+			birth[n] = true
+		}
+	}
+	for _, n := range params.Survival {
+		if n >= 0 && n <= 6 {
+			survival[n] = true
+		}
+	}
+
+	alive := NewHexSet()
+	for _, p := range cells {
+		if r.Float64() < params.FillProbability {
+			alive.Add(p)
+		}
+	}
+
+	for i := 0; i < params.Iterations; i++ {
+		next := NewHexSet()
+		for _, p := range cells {
+			n := 0
+			for _, nb := range p.Neighbours() {
+				if alive.Contains(nb) || (params.OutsideIsAlive && !params.Boundary.Contains(nb)) {
+					n++
+				}
+			}
+			if (alive.Contains(p) && survival[n]) || (!alive.Contains(p) && birth[n]) {
+				next.Add(p)
+			}
+		}
+		alive = next
+	}
+
+	if params.Connected {
+		alive = LargestConnectedComponent(alive)
+	}
+
+	return alive
+}
+
+// LargestConnectedComponent finds the largest connected component of a
+// HexSet. Ties are broken in favour of the component containing the earliest
+// HexCoord in ToOrderedList order.
+func LargestConnectedComponent(s *HexSet) *HexSet {
+	best := NewHexSet()
+	seen := NewHexSet()
+
+	for _, p := range s.ToOrderedList() {
+		if seen.Contains(p) {
+			continue
+		}
+
+		component, err := FindConnectedComponent(p, s.Predicate())
+		if err != nil {
+			panic(fmt.Errorf("unexpected error in FindConnectedComponent(): %v", err))
+		}
+		seen.InPlaceUnion(component)
+
+		if component.Size() > best.Size() {
+			best = component
+		}
+	}
+
+	return best
+}
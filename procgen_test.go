@@ -0,0 +1,92 @@
+package hex
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRandomWalkBlob(t *testing.T) {
+	boundary := NewHexSetAround(Origin, 6)
+
+	a, err := RandomWalkBlob(rand.New(rand.NewSource(7)), boundary, Origin, 40)
+	if err != nil {
+		t.Fatalf("RandomWalkBlob failed: %v", err)
+	}
+	if a.Size() != 40 || !boundary.ContainsSet(a) || !IsFullyConnected(a) {
+		t.Errorf("expected connected blob of size 40 within boundary, got:\n%s", a.Diagram())
+	}
+
+	b, _ := RandomWalkBlob(rand.New(rand.NewSource(7)), boundary, Origin, 40)
+	if !a.Equals(b) {
+		t.Errorf("same seed gave different blobs:\n%s\n\n%s", a.Diagram(), b.Diagram())
+	}
+
+	if _, err := RandomWalkBlob(rand.New(rand.NewSource(7)), boundary, Origin, boundary.Size()+1); err == nil {
+		t.Errorf("expected error for blob larger than boundary")
+	}
+}
+
+func TestRandomConnectedRegion(t *testing.T) {
+	boundary := NewHexSetAround(Origin, 8)
+	boundary.Filter(func(p HexCoord) bool { return p.X != 2 })
+
+	a, err := RandomConnectedRegion(rand.New(rand.NewSource(7)), boundary, Origin, 60)
+	if err != nil {
+		t.Fatalf("RandomConnectedRegion failed: %v", err)
+	}
+	if a.Size() != 60 || !boundary.ContainsSet(a) || !IsFullyConnected(a) {
+		t.Errorf("expected connected region of size 60 within boundary, got:\n%s", a.Diagram())
+	}
+
+	b, _ := RandomConnectedRegion(rand.New(rand.NewSource(7)), boundary, Origin, 60)
+	if !a.Equals(b) {
+		t.Errorf("same seed gave different regions")
+	}
+
+	if _, err := RandomConnectedRegion(rand.New(rand.NewSource(7)), boundary, Origin, 200); err == nil {
+		t.Errorf("expected error for region larger than the reachable part of the boundary")
+	}
+}
+
+func TestEmptyBlobs(t *testing.T) {
+	boundary := NewHexSetAround(Origin, 2)
+	generators := map[string]func(*rand.Rand, *HexSet, HexCoord, int) (*HexSet, error){
+		"RandomWalkBlob":        RandomWalkBlob,
+		"RandomConnectedRegion": RandomConnectedRegion,
+	}
+	for name, generate := range generators {
+		for _, size := range []int{0, -1} {
+			s, err := generate(rand.New(rand.NewSource(7)), boundary, Origin, size)
+			if err != nil || s.Size() != 0 {
+				t.Errorf("%s of size %d: expected an empty set, got %v (error %v)", name, size, s, err)
+			}
+		}
+	}
+}
+
+func TestGenerateCave(t *testing.T) {
+	params := &CaveParams{
+		Boundary:        NewHexSetAround(Origin, 10),
+		FillProbability: 0.55,
+		Birth:           []int{4, 5, 6},
+		Survival:        []int{3, 4, 5, 6},
+		Iterations:      4,
+		Connected:       true,
+	}
+
+	a := GenerateCave(rand.New(rand.NewSource(7)), params)
+	if a.Size() == 0 || !params.Boundary.ContainsSet(a) || !IsFullyConnected(a) {
+		t.Errorf("expected non-empty connected cave within boundary, got:\n%s", a.Diagram())
+	}
+
+	b := GenerateCave(rand.New(rand.NewSource(7)), params)
+	if !a.Equals(b) {
+		t.Errorf("same seed gave different caves")
+	}
+
+	params.Birth = nil
+	params.Survival = nil
+	if c := GenerateCave(rand.New(rand.NewSource(7)), params); c.Size() != 0 {
+		t.Errorf("expected nothing to survive without birth or survival rules, got %d hexes", c.Size())
+	}
+}
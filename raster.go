@@ -0,0 +1,180 @@
+package hex
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// RasterOrientation determines how hexes are oriented in a raster image.
+type RasterOrientation int
+
+const (
+	// FlatTop draws hexes with a flat edge at the top, as in GeoCoord space
+	// (north is up).
+	FlatTop RasterOrientation = iota
+	// PointyTop draws hexes with a vertex at the top; GeoCoord space is
+	// rotated a quarter turn clockwise (north is right).
+	PointyTop
+)
+
+// RasterParams provides parameters for rasterising hexes.
+type RasterParams struct {
+	// HexSize is the distance in pixels from the centre of a hex to a vertex.
+	HexSize     float64
+	Orientation RasterOrientation
+	// Margin is the number of pixels of background around the hexes.
+	Margin int
+	// Background is the colour of pixels not covered by any hex. If nil,
+	// they are transparent.
+	Background color.Color
+	// GridColor is the colour of lines drawn along hex edges. If nil, no grid
+	// lines are drawn.
+	GridColor color.Color
+	GridWidth float64
+	// Supersampling is the number of samples per pixel along each axis used
+	// to antialias edges. Zero means the default of 4.
+	Supersampling int
+}
+
+// hexNorm computes the "hex distance" of a point from the origin: the
+// hexagon around the origin contains exactly those points with hexNorm at
+// most 1.
+func hexNorm(p GeoCoord) float64 {
+	rv := math.Abs(p.Y)
+	for _, sign := range []float64{1, -1} {
+		d := math.Abs(sign*p.X*sqrt3/2 + p.Y/2)
+		if d > rv {
+			rv = d
+		}
+	}
+	return rv
+}
+
+// HexAtGeo finds the hex containing a GeoCoord.
+func HexAtGeo(g GeoCoord) HexCoord {
+	x0 := int(math.Floor(g.X/sqrt3 + 0.5))
+
+	var rv HexCoord
+	best := math.Inf(1)
+	for x := x0 - 1; x <= x0+1; x++ {
+		y := 2*int(math.Floor((g.Y-float64(x))/2+0.5)) + x
+		p := NewHex(x, y)
+		if d := p.Geo().Sub(g).SquareLength(); d < best {
+			rv, best = p, d
+		}
+	}
+	return rv
+}
+
+func (o RasterOrientation) toView(g GeoCoord) GeoCoord {
+	if o == PointyTop {
+		return GeoCoord{g.Y, -g.X}
+	}
+	return g
+}
+
+func (o RasterOrientation) fromView(v GeoCoord) GeoCoord {
+	if o == PointyTop {
+		return GeoCoord{-v.Y, v.X}
+	}
+	return v
+}
+
+// RasterizeHexes draws a list of hexes, filled with colours given by a
+// callback, into an image sized to fit them. Hexes for which the callback
+// returns nil are not drawn.
+func RasterizeHexes(params *RasterParams, hexes []HexCoord, colour func(HexCoord) color.Color) *image.RGBA {
+	scale := params.HexSize / (2 * hexHalfSideLength)
+	samples := params.Supersampling
+	if samples <= 0 {
+		samples = 4
+	}
+
+	colours := map[HexCoord]color.Color{}
+	var min, max GeoCoord
+	for _, p := range hexes {
+		c := colour(p)
+		if c == nil {
+			continue
+		}
+		colours[p] = c
+
+		for j := 0; j < 6; j++ {
+			v := params.Orientation.toView(p.Vertex(j))
+			if len(colours) == 1 && j == 0 {
+				min, max = v, v
+			}
+			min.X = math.Min(min.X, v.X)
+			min.Y = math.Min(min.Y, v.Y)
+			max.X = math.Max(max.X, v.X)
+			max.Y = math.Max(max.Y, v.Y)
+		}
+	}
+
+	margin := float64(params.Margin)
+	width := int(math.Ceil((max.X-min.X)*scale + 2*margin))
+	height := int(math.Ceil((max.Y-min.Y)*scale + 2*margin))
+	if len(colours) == 0 {
+		width, height = 2*params.Margin, 2*params.Margin
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	sample := func(px, py float64) color.Color {
+		v := GeoCoord{min.X + (px-margin)/scale, max.Y - (py-margin)/scale}
+		g := params.Orientation.fromView(v)
+		p := HexAtGeo(g)
+
+		c, ok := colours[p]
+		if !ok {
+			return params.Background
+		}
+
+		if params.GridColor != nil {
+			edgeDistance := (1 - hexNorm(g.Sub(p.Geo()))) * scale
+			if edgeDistance < params.GridWidth/2 {
+				return params.GridColor
+			}
+		}
+
+		return c
+	}
+
+	for py := 0; py < height; py++ {
+		for px := 0; px < width; px++ {
+			var r, g, b, a uint32
+			for i := 0; i < samples; i++ {
+				for j := 0; j < samples; j++ {
+					sx := float64(px) + (float64(i)+0.5)/float64(samples)
+					sy := float64(py) + (float64(j)+0.5)/float64(samples)
+					c := sample(sx, sy)
+					if c == nil {
+						continue
+					}
+					cr, cg, cb, ca := c.RGBA()
+					r += cr
+					g += cg
+					b += cb
+					a += ca
+				}
+			}
+			n := uint32(samples * samples)
+			img.SetRGBA(px, py, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+
+	return img
+}
+
+// RasterizeHexSet draws the hexes in a HexSet, filled with a single colour,
+// into an image sized to fit them.
+func RasterizeHexSet(params *RasterParams, s *HexSet, c color.Color) *image.RGBA {
+	return RasterizeHexes(params, s.ToOrderedList(), func(HexCoord) color.Color {
+		return c
+	})
+}
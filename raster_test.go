@@ -0,0 +1,79 @@
+package hex
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestHexAtGeo(t *testing.T) {
+	for _, p := range HexDisk(5) {
+		for i := 0; i < 6; i++ {
+			g := p.Geo().Add(p.Vertex(i).Sub(p.Geo()).Scaled(0.9))
+			if got := HexAtGeo(g); got != p {
+				t.Errorf("expected %v near vertex %d of %v to be in it, got %v", g, i, p, got)
+			}
+		}
+	}
+}
+
+func TestRasterizeHexSet(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	white := color.RGBA{255, 255, 255, 255}
+
+	img := RasterizeHexSet(&RasterParams{
+		HexSize:    20,
+		Background: white,
+	}, NewHexSetSingleton(Origin), red)
+
+	b := img.Bounds()
+	if b.Dx() != 40 || b.Dy() != 35 {
+		t.Errorf("expected 40x35 image for a single flat-top hex, got %v", b)
+	}
+	if got := img.RGBAAt(b.Dx()/2, b.Dy()/2); got != red {
+		t.Errorf("expected centre to be red, got %v", got)
+	}
+	if got := img.RGBAAt(0, 0); got != white {
+		t.Errorf("expected corner to be background, got %v", got)
+	}
+
+	blended := false
+	for x := 0; x < b.Dx(); x++ {
+		c := img.RGBAAt(x, 5)
+		if c.G > 0 && c.G < 255 {
+			blended = true
+		}
+	}
+	if !blended {
+		t.Errorf("expected antialiased pixels along slanted edges")
+	}
+
+	pointy := RasterizeHexSet(&RasterParams{
+		HexSize:     20,
+		Orientation: PointyTop,
+	}, NewHexSetSingleton(Origin), red)
+	if pb := pointy.Bounds(); pb.Dx() != b.Dy() || pb.Dy() != b.Dx() {
+		t.Errorf("expected pointy-top image to be %dx%d, got %v", b.Dy(), b.Dx(), pb)
+	}
+}
+
+func TestRasterizeGridLines(t *testing.T) {
+	black := color.RGBA{0, 0, 0, 255}
+	green := color.RGBA{0, 255, 0, 255}
+
+	img := RasterizeHexes(&RasterParams{
+		HexSize:   20,
+		GridColor: black,
+		GridWidth: 2,
+	}, []HexCoord{Origin, NewHex(0, 2)}, func(HexCoord) color.Color {
+		return green
+	})
+
+	// The shared edge of the two hexes runs horizontally through the middle.
+	b := img.Bounds()
+	if got := img.RGBAAt(b.Dx()/2, b.Dy()/2); got.G > 128 {
+		t.Errorf("expected grid line between hexes, got %v", got)
+	}
+	if got := img.RGBAAt(b.Dx()/2, b.Dy()/4); got != green {
+		t.Errorf("expected hex interior to be green, got %v", got)
+	}
+}
@@ -1,108 +1,129 @@
 package hex
 
 import (
-	"fmt"
+	"errors"
+)
+
+var (
+	// ErrNoPath is returned when no goal can be reached.
+	ErrNoPath = errors.New("no path found")
+
+	// ErrCostLimit is returned when no goal can be reached within the
+	// cost limit.
+	ErrCostLimit = errors.New("no path found within cost limit")
+)
 
-	"github.com/oleiade/lane"
+// AStarTieBreak selects how A* chooses between open nodes with equal
+// estimated total cost.
+type AStarTieBreak int
+
+const (
+	// TieBreakLargerCost prefers nodes further along their path, which
+	// typically reaches the goal after expanding fewer nodes.
+	TieBreakLargerCost AStarTieBreak = iota
+	// TieBreakSmallerCost prefers nodes nearer to the start.
+	TieBreakSmallerCost
+	// TieBreakStraight prefers nodes reached with fewer changes of
+	// direction. This is a heuristic: turns are counted per hex rather than
+	// per direction of arrival, so the straightest of equally cheap paths
+	// is usually, but not always, chosen.
+	TieBreakStraight
 )
 
-// AStarParams provides parameters for an A* search.
+// AStarFallback selects which partial path, if any, A* returns when no goal
+// can be reached.
+type AStarFallback int
+
+const (
+	// NoFallback returns no path.
+	NoFallback AStarFallback = iota
+	// FallbackNearestHeuristic returns a path to the explored node with the
+	// smallest heuristic, i.e. the one estimated to be nearest a goal.
+	FallbackNearestHeuristic
+	// FallbackNearestEstimate returns a path to the explored node with the
+	// smallest cost plus heuristic.
+	FallbackNearestEstimate
+)
+
+// AStarParams provides parameters for an A* search. If ZOC is set, paths
+// must obey its zone-of-control rules. If Fallback is set, a partial path is
+// returned along with the error when no goal can be reached. If Stats is set,
+// it is filled in whether or not the search succeeds. If Tracer is set, it
+// is notified of the progress of the search.
 type AStarParams struct {
 	Start     *HexSet
 	IsGoal    func(HexCoord) bool
 	Cost      func(HexCoord, HexCoord) (float64, bool)
 	Heuristic func(HexCoord) float64
 	MaxCost   float64
+	ZOC       *ZoneOfControl
+	TieBreak  AStarTieBreak
+	Fallback  AStarFallback
+	Limits    SearchLimits
+	Stats     *SearchStats
+	Tracer    SearchTracer
 }
 
-// AStarResult represents the result of an A* search.
+// AStarResult represents the result of an A* search. Partial is set if the
+// path does not reach a goal.
 type AStarResult struct {
-	Path []HexCoord
-	Cost float64
+	Path    []HexCoord
+	Cost    float64
+	Partial bool
 }
 
-type aStarNode struct {
-	point     HexCoord
-	cost      float64
-	heuristic float64
-}
-
-// AStar performs an A* search.
+// AStar performs an A* search. Open nodes are ordered by estimated total
+// cost, with ties broken as given by TieBreak. Errors are ErrNoPath,
+// ErrCostLimit, ErrSearchBudget or the error of the context in Limits.
 func AStar(params *AStarParams) (*AStarResult, error) {
-	closed := NewHexSet()
-	open := lane.NewPQueue(lane.MINPQ)
-	openMap := map[HexCoord]*aStarNode{}
-	trail := map[HexCoord]*HexCoord{}
-
-	insertNode := func(n *aStarNode) {
-		k := int(10000 * (n.cost + n.heuristic))
-		open.Push(n, k)
-		openMap[n.point] = n
-	}
-
-	for _, p := range params.Start.ToList() {
-		trail[p] = nil
-		node := aStarNode{
-			point:     p,
-			cost:      0,
-			heuristic: params.Heuristic(p),
-		}
-		insertNode(&node)
+	var tracer StateTracer[HexCoord]
+	if params.Tracer != nil {
+		tracer = params.Tracer
 	}
 
-	for open.Size() > 0 {
-		currentNode, _ := open.Pop()
-		current := currentNode.(*aStarNode)
-
-		if params.MaxCost != 0 && current.cost > params.MaxCost {
-			return nil, fmt.Errorf("no path found within cost limit")
-		}
-
-		if params.IsGoal(current.point) {
-			var rpath []HexCoord
-			for p := &current.point; p != nil; p = trail[*p] {
-				rpath = append(rpath, *p)
+	result, err := StateSearch(&StateSearchParams[HexCoord]{
+		Start:  params.Start.ToOrderedList(),
+		IsGoal: params.IsGoal,
+		Successors: func(p HexCoord) []StateStep[HexCoord] {
+			if !params.ZOC.CanLeave(p, params.Start.Contains(p)) {
+				return nil
 			}
 
-			result := AStarResult{}
-			result.Cost = current.cost
-			for i := len(rpath) - 1; i >= 0; i-- {
-				result.Path = append(result.Path, rpath[i])
-			}
-
-			return &result, nil
-		}
-
-		closed.Add(current.point)
-
-		for _, neighbour := range current.point.Neighbours() {
-			if closed.Contains(neighbour) {
-				continue
-			}
-
-			stepCost, ok := params.Cost(current.point, neighbour)
-			if !ok {
-				continue
-			}
-
-			node := aStarNode{
-				point: neighbour,
-				cost:  current.cost + stepCost,
-			}
-			prevNode, present := openMap[node.point]
-			if present {
-				if node.cost >= prevNode.cost {
+			var rv []StateStep[HexCoord]
+			for _, d := range OrderedDirections {
+				neighbour := p.AddDelta(Directions[d])
+				stepCost, ok := params.Cost(p, neighbour)
+				if !ok {
 					continue
 				}
-				node.heuristic = prevNode.heuristic
-			} else {
-				node.heuristic = params.Heuristic(node.point)
+				zocCost, ok := params.ZOC.StepCost(p, neighbour)
+				if !ok {
+					continue
+				}
+				rv = append(rv, StateStep[HexCoord]{
+					State:  neighbour,
+					Cost:   stepCost + zocCost,
+					Action: int(d),
+				})
 			}
-
-			insertNode(&node)
-			trail[node.point] = &current.point
-		}
+			return rv
+		},
+		Heuristic: params.Heuristic,
+		Order:     BestFirst,
+		MaxCost:   params.MaxCost,
+		TieBreak:  params.TieBreak,
+		Fallback:  params.Fallback,
+		Limits:    params.Limits,
+		Stats:     params.Stats,
+		Tracer:    tracer,
+	})
+	if result == nil {
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("no path found")
+	return &AStarResult{
+		Path:    result.Path,
+		Cost:    result.Cost,
+		Partial: result.Partial,
+	}, err
 }
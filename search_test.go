@@ -1,7 +1,12 @@
 package hex
 
 import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
 	"testing"
+	"time"
 )
 
 func TestBasicAStar(t *testing.T) {
@@ -75,3 +80,211 @@ func TestBasicAStarFailing(t *testing.T) {
 		t.Errorf("expected failure, got success")
 	}
 }
+
+func TestAStarSmallCosts(t *testing.T) {
+	// Costs this small were once indistinguishable in the priority queue.
+	goal := NewHex(0, 4)
+	result, err := AStar(&AStarParams{
+		Start:  NewHexSetSingleton(Origin),
+		IsGoal: func(p HexCoord) bool { return p == goal },
+		Cost: func(a, b HexCoord) (float64, bool) {
+			if b == NewHex(0, 2) {
+				return 1e-5, true
+			}
+			return 1e-6, true
+		},
+		Heuristic: func(p HexCoord) float64 { return 0 },
+	})
+	if err != nil {
+		t.Fatalf("A* failed: %v", err)
+	}
+	if len(result.Path) != 4 || math.Abs(result.Cost-3e-6) > 1e-12 {
+		t.Errorf("expected path of cost 3e-6 avoiding (0,2), got %v with cost %v", result.Path, result.Cost)
+	}
+}
+
+func TestAStarTieBreaking(t *testing.T) {
+	goal := NewHex(6, 0)
+	for _, tieBreak := range []AStarTieBreak{TieBreakLargerCost, TieBreakSmallerCost, TieBreakStraight} {
+		result, err := AStar(&AStarParams{
+			Start:     NewHexSetSingleton(Origin),
+			IsGoal:    func(p HexCoord) bool { return p == goal },
+			Cost:      func(a, b HexCoord) (float64, bool) { return 1, true },
+			Heuristic: func(p HexCoord) float64 { return float64(p.StepsTo(goal)) },
+			TieBreak:  tieBreak,
+		})
+		if err != nil {
+			t.Fatalf("A* failed: %v", err)
+		}
+		if result.Cost != 6 {
+			t.Errorf("expected cost 6 with tie-break %v, got %v", tieBreak, result.Cost)
+		}
+
+		if tieBreak != TieBreakStraight {
+			continue
+		}
+		turns := 0
+		for i := 2; i < len(result.Path); i++ {
+			if result.Path[i].Minus(result.Path[i-1]) != result.Path[i-1].Minus(result.Path[i-2]) {
+				turns++
+			}
+		}
+		if turns != 1 {
+			t.Errorf("expected straightest path to turn once, got %v", result.Path)
+		}
+	}
+}
+
+func TestAStarFallback(t *testing.T) {
+	goal := NewHex(0, 20)
+	region := NewHexSetAround(Origin, 5)
+	params := &AStarParams{
+		Start:     NewHexSetSingleton(Origin),
+		IsGoal:    func(p HexCoord) bool { return p == goal },
+		Cost:      func(a, b HexCoord) (float64, bool) { return 1, region.Contains(b) },
+		Heuristic: func(p HexCoord) float64 { return float64(p.StepsTo(goal)) },
+	}
+
+	result, err := AStar(params)
+	if !errors.Is(err, ErrNoPath) || result != nil {
+		t.Errorf("expected ErrNoPath and no result, got %v and %v", err, result)
+	}
+
+	for _, fallback := range []AStarFallback{FallbackNearestHeuristic, FallbackNearestEstimate} {
+		params.Fallback = fallback
+		result, err = AStar(params)
+		if !errors.Is(err, ErrNoPath) {
+			t.Errorf("expected ErrNoPath, got %v", err)
+		}
+		if result == nil || !result.Partial || result.Path[len(result.Path)-1] != NewHex(0, 10) || result.Cost != 5 {
+			t.Errorf("expected partial path to (0,10), got %+v", result)
+		}
+	}
+
+	params.MaxCost = 3
+	result, err = AStar(params)
+	if !errors.Is(err, ErrCostLimit) {
+		t.Errorf("expected ErrCostLimit, got %v", err)
+	}
+	if result == nil || !result.Partial || result.Path[len(result.Path)-1] != NewHex(0, 6) {
+		t.Errorf("expected partial path to (0,6), got %+v", result)
+	}
+}
+
+func TestSearchLimits(t *testing.T) {
+	always := func(_, _ HexCoord) bool { return true }
+	never := func(HexCoord) bool { return false }
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	searches := map[string]func(*SearchParams) ([]HexCoord, error){
+		"BFS": BreadthFirstSearchWithParams,
+		"DFS": DepthFirstSearchWithParams,
+	}
+	for name, search := range searches {
+		var stats SearchStats
+		_, err := search(&SearchParams{
+			Start:       NewHexSetSingleton(Origin),
+			IsSteppable: always,
+			IsGoal:      never,
+			Limits:      SearchLimits{MaxExpansions: 500},
+			Stats:       &stats,
+		})
+		if !errors.Is(err, ErrSearchBudget) || stats.Expanded != 500 || stats.MaxOpen == 0 {
+			t.Errorf("%s: expected budget to be exhausted after 500 expansions, got %v with %+v", name, err, stats)
+		}
+
+		_, err = search(&SearchParams{
+			Start:       NewHexSetSingleton(Origin),
+			IsSteppable: always,
+			IsGoal:      never,
+			Limits:      SearchLimits{Timeout: time.Millisecond},
+		})
+		if !errors.Is(err, ErrSearchBudget) {
+			t.Errorf("%s: expected timeout, got %v", name, err)
+		}
+
+		_, err = search(&SearchParams{
+			Start:       NewHexSetSingleton(Origin),
+			IsSteppable: always,
+			IsGoal:      never,
+			Limits:      SearchLimits{Context: cancelled},
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected cancellation, got %v", name, err)
+		}
+
+		region := NewHexSetAround(Origin, 2)
+		_, err = search(&SearchParams{
+			Start:       NewHexSetSingleton(Origin),
+			IsSteppable: func(_, b HexCoord) bool { return region.Contains(b) },
+			IsGoal:      never,
+		})
+		if !errors.Is(err, ErrNoPath) {
+			t.Errorf("%s: expected ErrNoPath, got %v", name, err)
+		}
+	}
+}
+
+func TestAStarLimitsAndStats(t *testing.T) {
+	params := benchmarkAStarParams(0)
+	var stats SearchStats
+	params.Stats = &stats
+	if _, err := AStar(params); err != nil {
+		t.Fatalf("A* failed: %v", err)
+	}
+	if stats.Expanded < 60 || stats.MaxOpen == 0 {
+		t.Errorf("implausible stats: %+v", stats)
+	}
+
+	params.Limits.MaxExpansions = 10
+	params.Fallback = FallbackNearestHeuristic
+	result, err := AStar(params)
+	if !errors.Is(err, ErrSearchBudget) || stats.Expanded != 10 {
+		t.Errorf("expected budget to be exhausted after 10 expansions, got %v with %+v", err, stats)
+	}
+	if result == nil || !result.Partial {
+		t.Errorf("expected partial path, got %+v", result)
+	}
+}
+
+func benchmarkAStarParams(obstacleProbability float64) *AStarParams {
+	r := rand.New(rand.NewSource(1))
+	region := NewHexSetAround(Origin, 40)
+	start, goal := NewHex(-30, 0), NewHex(30, 0)
+	blocked := NewHexSet()
+	for _, p := range region.ToOrderedList() {
+		if p != start && p != goal && r.Float64() < obstacleProbability {
+			blocked.Add(p)
+		}
+	}
+
+	return &AStarParams{
+		Start:  NewHexSetSingleton(start),
+		IsGoal: func(p HexCoord) bool { return p == goal },
+		Cost: func(a, b HexCoord) (float64, bool) {
+			return 1, region.Contains(b) && !blocked.Contains(b)
+		},
+		Heuristic: func(p HexCoord) float64 { return float64(p.StepsTo(goal)) },
+	}
+}
+
+func BenchmarkAStarOpen(b *testing.B) {
+	params := benchmarkAStarParams(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := AStar(params); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAStarObstacles(b *testing.B) {
+	params := benchmarkAStarParams(0.3)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := AStar(params); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package hex
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrSearchBudget is returned when a search runs out of its node-expansion
+// or wall-clock budget before reaching a goal.
+var ErrSearchBudget = errors.New("search budget exhausted")
+
+// SearchLimits bounds a search. If Context is set, the search stops with
+// the context's error when it is done. MaxExpansions and Timeout limit the
+// number of nodes expanded and the time taken, if nonzero.
+type SearchLimits struct {
+	Context       context.Context
+	MaxExpansions int
+	Timeout       time.Duration
+}
+
+// SearchStats represents statistics about a search: the number of nodes
+// expanded, the largest size of the open set, and the time taken.
+type SearchStats struct {
+	Expanded int
+	MaxOpen  int
+	Duration time.Duration
+}
+
+// How many expansions between checks of the clock and context.
+const searchCheckInterval = 64
+
+// searchMonitor enforces SearchLimits and gathers SearchStats.
+type searchMonitor struct {
+	limits   SearchLimits
+	stats    SearchStats
+	output   *SearchStats
+	started  time.Time
+	deadline time.Time
+}
+
+func newSearchMonitor(limits SearchLimits, output *SearchStats) *searchMonitor {
+	m := &searchMonitor{
+		limits:  limits,
+		output:  output,
+		started: time.Now(),
+	}
+	if limits.Timeout != 0 {
+		m.deadline = m.started.Add(limits.Timeout)
+	}
+	return m
+}
+
+// expand records the expansion of a node given the current size of the open
+// set, returning an error if the search should stop.
+func (m *searchMonitor) expand(open int) error {
+	if open > m.stats.MaxOpen {
+		m.stats.MaxOpen = open
+	}
+	if m.limits.MaxExpansions != 0 && m.stats.Expanded >= m.limits.MaxExpansions {
+		return ErrSearchBudget
+	}
+	m.stats.Expanded++
+
+	if (m.stats.Expanded-1)%searchCheckInterval == 0 {
+		if m.limits.Context != nil {
+			if err := m.limits.Context.Err(); err != nil {
+				return err
+			}
+		}
+		if !m.deadline.IsZero() && time.Now().After(m.deadline) {
+			return ErrSearchBudget
+		}
+	}
+
+	return nil
+}
+
+// finish completes the SearchStats and writes them to the output, if any.
+func (m *searchMonitor) finish() {
+	m.stats.Duration = time.Since(m.started)
+	if m.output != nil {
+		*m.output = m.stats
+	}
+}
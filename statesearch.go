@@ -0,0 +1,275 @@
+package hex
+
+// StateSearchOrder selects the order in which a state-space search expands
+// states.
+type StateSearchOrder int
+
+const (
+	// BestFirst expands states in order of cost plus heuristic, as in A*.
+	BestFirst StateSearchOrder = iota
+	// BreadthFirst expands states in the order they were first reached.
+	BreadthFirst
+	// DepthFirst expands the most recently reached state first.
+	DepthFirst
+)
+
+// StateStep is a step from a state to a successor state. Action labels the
+// kind of step (e.g. its direction); a change of Action between consecutive
+// steps counts as a turn for TieBreakStraight.
+type StateStep[S comparable] struct {
+	State  S
+	Cost   float64
+	Action int
+}
+
+// StateTracer is notified of the progress of a state-space search, like
+// SearchTracer (which is a StateTracer for HexCoords).
+type StateTracer[S comparable] interface {
+	Push(s S, cost float64)
+	Pop(s S, cost float64)
+	Expand(s S)
+	Path(path []S)
+}
+
+// StateSearchParams provides parameters for a search over an arbitrary state
+// space, in which each state's outgoing steps are given by Successors. The
+// Heuristic is optional, and only used for BestFirst searches. Other fields
+// are as for AStarParams.
+type StateSearchParams[S comparable] struct {
+	Start      []S
+	IsGoal     func(S) bool
+	Successors func(S) []StateStep[S]
+	Heuristic  func(S) float64
+	Order      StateSearchOrder
+	MaxCost    float64
+	TieBreak   AStarTieBreak
+	Fallback   AStarFallback
+	Limits     SearchLimits
+	Stats      *SearchStats
+	Tracer     StateTracer[S]
+}
+
+// StateSearchResult represents the result of a state-space search. Partial
+// is set if the path does not reach a goal.
+type StateSearchResult[S comparable] struct {
+	Path    []S
+	Cost    float64
+	Partial bool
+}
+
+type stateNode[S comparable] struct {
+	state     S
+	parent    *stateNode[S]
+	cost      float64
+	heuristic float64
+	turns     int
+	action    int
+	closed    bool
+	seq       int
+	index     int
+}
+
+func stateNodeLess[S comparable](tieBreak AStarTieBreak) func(a, b *stateNode[S]) bool {
+	return func(a, b *stateNode[S]) bool {
+		if fa, fb := a.cost+a.heuristic, b.cost+b.heuristic; fa != fb {
+			return fa < fb
+		}
+		switch tieBreak {
+		case TieBreakStraight:
+			if a.turns != b.turns {
+				return a.turns < b.turns
+			}
+			if a.cost != b.cost {
+				return a.cost > b.cost
+			}
+		case TieBreakSmallerCost:
+			if a.cost != b.cost {
+				return a.cost < b.cost
+			}
+		default:
+			if a.cost != b.cost {
+				return a.cost > b.cost
+			}
+		}
+		return a.seq < b.seq
+	}
+}
+
+// fallbackLess checks whether a node is a better partial path destination
+// than another.
+func fallbackLess[S comparable](fallback AStarFallback, a, b *stateNode[S]) bool {
+	if fallback == FallbackNearestEstimate {
+		if fa, fb := a.cost+a.heuristic, b.cost+b.heuristic; fa != fb {
+			return fa < fb
+		}
+	}
+	if a.heuristic != b.heuristic {
+		return a.heuristic < b.heuristic
+	}
+	return a.cost < b.cost
+}
+
+// stateFrontier is the open set of a search, in the given order.
+type stateFrontier[S comparable] struct {
+	order StateSearchOrder
+	heap  *priorityQueue[*stateNode[S]]
+	list  []*stateNode[S]
+}
+
+func (f *stateFrontier[S]) Len() int {
+	if f.order == BestFirst {
+		return f.heap.Len()
+	}
+	return len(f.list)
+}
+
+func (f *stateFrontier[S]) Push(n *stateNode[S]) {
+	if f.order == BestFirst {
+		f.heap.Push(n)
+		return
+	}
+	f.list = append(f.list, n)
+}
+
+func (f *stateFrontier[S]) Pop() *stateNode[S] {
+	switch f.order {
+	case BestFirst:
+		return f.heap.Pop()
+	case BreadthFirst:
+		rv := f.list[0]
+		f.list = f.list[1:]
+		return rv
+	}
+	rv := f.list[len(f.list)-1]
+	f.list = f.list[:len(f.list)-1]
+	return rv
+}
+
+// StateSearch searches for a path from a start state to a goal state. In a
+// BestFirst search, a node's cost may be improved until it is expanded; in
+// other orders, each state is reached only once. Errors are as for AStar.
+func StateSearch[S comparable](params *StateSearchParams[S]) (*StateSearchResult[S], error) {
+	monitor := newSearchMonitor(params.Limits, params.Stats)
+	defer monitor.finish()
+	tracer := tracerOrNull(params.Tracer)
+
+	heuristic := params.Heuristic
+	if heuristic == nil || params.Order != BestFirst {
+		heuristic = func(S) float64 { return 0 }
+	}
+
+	frontier := &stateFrontier[S]{order: params.Order}
+	if params.Order == BestFirst {
+		frontier.heap = newIndexedPriorityQueue(stateNodeLess[S](params.TieBreak), func(n *stateNode[S], i int) { n.index = i })
+	}
+	nodes := map[S]*stateNode[S]{}
+	seq := 0
+
+	for _, s := range params.Start {
+		if _, ok := nodes[s]; ok {
+			continue
+		}
+		node := &stateNode[S]{
+			state:     s,
+			heuristic: heuristic(s),
+			seq:       seq,
+		}
+		seq++
+		nodes[s] = node
+		frontier.Push(node)
+		tracer.Push(s, 0)
+	}
+
+	resultTo := func(n *stateNode[S]) *StateSearchResult[S] {
+		var rpath []S
+		for p := n; p != nil; p = p.parent {
+			rpath = append(rpath, p.state)
+		}
+
+		result := StateSearchResult[S]{}
+		result.Cost = n.cost
+		for i := len(rpath) - 1; i >= 0; i-- {
+			result.Path = append(result.Path, rpath[i])
+		}
+		tracer.Path(result.Path)
+		return &result
+	}
+
+	var best *stateNode[S]
+	fail := func(err error) (*StateSearchResult[S], error) {
+		if params.Fallback == NoFallback || best == nil {
+			return nil, err
+		}
+		result := resultTo(best)
+		result.Partial = true
+		return result, err
+	}
+
+	for frontier.Len() > 0 {
+		if err := monitor.expand(frontier.Len()); err != nil {
+			return fail(err)
+		}
+
+		current := frontier.Pop()
+		tracer.Pop(current.state, current.cost)
+
+		if params.MaxCost != 0 && current.cost > params.MaxCost {
+			return fail(ErrCostLimit)
+		}
+
+		if params.IsGoal(current.state) {
+			return resultTo(current), nil
+		}
+
+		if best == nil || fallbackLess(params.Fallback, current, best) {
+			best = current
+		}
+
+		current.closed = true
+		tracer.Expand(current.state)
+
+		for _, step := range params.Successors(current.state) {
+			node, present := nodes[step.State]
+			if present && (node.closed || params.Order != BestFirst) {
+				continue
+			}
+
+			cost := current.cost + step.Cost
+			turns := current.turns
+			if current.parent != nil && current.action != step.Action {
+				turns++
+			}
+
+			if present {
+				better := cost < node.cost
+				if params.TieBreak == TieBreakStraight && cost == node.cost && turns < node.turns {
+					better = true
+				}
+				if !better {
+					continue
+				}
+			} else {
+				node = &stateNode[S]{
+					state:     step.State,
+					heuristic: heuristic(step.State),
+					seq:       seq,
+				}
+				seq++
+				nodes[step.State] = node
+			}
+
+			node.parent = current
+			node.cost = cost
+			node.turns = turns
+			node.action = step.Action
+			if present {
+				frontier.heap.Fix(node.index)
+			} else {
+				frontier.Push(node)
+			}
+			tracer.Push(step.State, cost)
+		}
+	}
+
+	return fail(ErrNoPath)
+}
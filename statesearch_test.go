@@ -0,0 +1,75 @@
+package hex
+
+import (
+	"testing"
+)
+
+type keyState struct {
+	P      HexCoord
+	HasKey bool
+}
+
+func TestStateSearchWithKey(t *testing.T) {
+	region := NewHexSetAround(Origin, 4)
+	door := NewHex(0, 4)
+	key := NewHex(-4, 0)
+	goal := NewHex(0, 6)
+
+	// The goal is behind a door in a wall across the region, and the key
+	// lies in the opposite direction.
+	wall := NewHexSet()
+	for _, p := range region.Enumerate() {
+		if p.Y == 4 || p.Y == 5 {
+			wall.Add(p)
+		}
+	}
+	wall.Remove(door)
+
+	successors := func(s keyState) []StateStep[keyState] {
+		var rv []StateStep[keyState]
+		for _, d := range OrderedDirections {
+			nb := s.P.AddDelta(Directions[d])
+			if !region.Contains(nb) || wall.Contains(nb) || (nb == door && !s.HasKey) {
+				continue
+			}
+			rv = append(rv, StateStep[keyState]{
+				State:  keyState{P: nb, HasKey: s.HasKey || nb == key},
+				Cost:   1,
+				Action: int(d),
+			})
+		}
+		return rv
+	}
+
+	for _, order := range []StateSearchOrder{BestFirst, BreadthFirst, DepthFirst} {
+		result, err := StateSearch(&StateSearchParams[keyState]{
+			Start:      []keyState{{P: Origin}},
+			IsGoal:     func(s keyState) bool { return s.P == goal },
+			Successors: successors,
+			Heuristic:  func(s keyState) float64 { return float64(s.P.StepsTo(goal)) },
+			Order:      order,
+		})
+		if err != nil {
+			t.Fatalf("search in order %v failed: %v", order, err)
+		}
+
+		visitedKey := false
+		for i, s := range result.Path {
+			if s.P == key {
+				visitedKey = true
+			}
+			if s.P == door && !visitedKey {
+				t.Errorf("path passes door before key: %v", result.Path)
+			}
+			if i > 0 && s.P.StepsTo(result.Path[i-1].P) != 1 {
+				t.Errorf("path is not connected: %v", result.Path)
+			}
+		}
+		if result.Cost != float64(len(result.Path)-1) {
+			t.Errorf("cost %v does not match path length %d", result.Cost, len(result.Path))
+		}
+		if order != DepthFirst && result.Cost != 9 {
+			t.Errorf("expected shortest path of 9 steps in order %v, got %v", order, result.Cost)
+		}
+	}
+}
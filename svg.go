@@ -0,0 +1,271 @@
+package hex
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// SVGStyle describes how a shape is drawn on an SVGCanvas. Empty colours
+// are not drawn, and an Opacity of zero means fully opaque.
+type SVGStyle struct {
+	Fill        string
+	Stroke      string
+	StrokeWidth float64
+	Opacity     float64
+}
+
+func (s SVGStyle) attributes() string {
+	fill := s.Fill
+	if fill == "" {
+		fill = "none"
+	}
+	stroke := s.Stroke
+	if stroke == "" {
+		stroke = "none"
+	}
+	rv := fmt.Sprintf(`fill="%s" stroke="%s"`, svgEscape(fill), svgEscape(stroke))
+	if s.StrokeWidth > 0 {
+		rv += fmt.Sprintf(` stroke-width="%.2f"`, s.StrokeWidth)
+	}
+	if s.Opacity > 0 {
+		rv += fmt.Sprintf(` opacity="%.2f"`, s.Opacity)
+	}
+	return rv
+}
+
+// svgTransform maps geometric coordinates to SVG pixel coordinates.
+type svgTransform func(GeoCoord) (float64, float64)
+
+func (t svgTransform) point(p GeoCoord) string {
+	x, y := t(p)
+	return fmt.Sprintf("%.2f,%.2f", x, y)
+}
+
+// svgWedgeStep is the maximum angle (in radians) between the points used to
+// approximate arcs.
+const svgWedgeStep = math.Pi / 36
+
+// SVGCanvas accumulates shapes on the plane, to be rendered as an SVG image.
+// The image is sized to fit everything drawn on it, with north upwards.
+type SVGCanvas struct {
+	// Scale is the number of pixels per unit of geometric distance (the
+	// distance between the centres of two vertically adjacent hexes is 2).
+	Scale float64
+	// Margin is the number of pixels of empty space around the drawing.
+	Margin float64
+
+	elements       []func(transform svgTransform) string
+	min, max       GeoCoord
+	hasBoundingBox bool
+}
+
+// NewSVGCanvas creates a new (empty) SVGCanvas.
+func NewSVGCanvas(scale float64) *SVGCanvas {
+	return &SVGCanvas{
+		Scale:  scale,
+		Margin: scale,
+	}
+}
+
+func svgEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+func (c *SVGCanvas) include(points ...GeoCoord) {
+	for _, p := range points {
+		if !c.hasBoundingBox {
+			c.min, c.max = p, p
+			c.hasBoundingBox = true
+			continue
+		}
+		c.min.X = math.Min(c.min.X, p.X)
+		c.min.Y = math.Min(c.min.Y, p.Y)
+		c.max.X = math.Max(c.max.X, p.X)
+		c.max.Y = math.Max(c.max.Y, p.Y)
+	}
+}
+
+func (c *SVGCanvas) polygon(points []GeoCoord, style SVGStyle) {
+	c.include(points...)
+	c.elements = append(c.elements, func(transform svgTransform) string {
+		var coords []string
+		for _, p := range points {
+			coords = append(coords, transform.point(p))
+		}
+		return fmt.Sprintf(`<polygon points="%s" %s/>`, strings.Join(coords, " "), style.attributes())
+	})
+}
+
+func hexVertices(p HexCoord) []GeoCoord {
+	rv := make([]GeoCoord, 6)
+	for i := range rv {
+		rv[i] = p.Vertex(i)
+	}
+	return rv
+}
+
+// Hex draws a single hex cell.
+func (c *SVGCanvas) Hex(p HexCoord, style SVGStyle) {
+	c.polygon(hexVertices(p), style)
+}
+
+// HexSet draws each of the hex cells in a HexSet.
+func (c *SVGCanvas) HexSet(s *HexSet, style SVGStyle) {
+	for _, p := range s.ToOrderedList() {
+		c.Hex(p, style)
+	}
+}
+
+// Cells draws each of a list of hex cells with its own fill colour and
+// label, as given by a callback. Empty labels are not drawn.
+func (c *SVGCanvas) Cells(ps []HexCoord, stroke string, f func(HexCoord) (fill, label string)) {
+	for _, p := range ps {
+		fill, label := f(p)
+		c.Hex(p, SVGStyle{Fill: fill, Stroke: stroke})
+		if label != "" {
+			c.Label(p, label)
+		}
+	}
+}
+
+// Label draws a text label centred on a hex cell.
+func (c *SVGCanvas) Label(p HexCoord, text string) {
+	center := p.Geo()
+	c.include(center)
+	c.elements = append(c.elements, func(transform svgTransform) string {
+		x, y := transform(center)
+		return fmt.Sprintf(`<text x="%.2f" y="%.2f" font-size="%.2f" text-anchor="middle" dominant-baseline="central">%s</text>`, x, y, 0.8*c.Scale, svgEscape(text))
+	})
+}
+
+// Path draws a line through the centres of a sequence of hexes.
+func (c *SVGCanvas) Path(path []HexCoord, style SVGStyle) {
+	var points []GeoCoord
+	for _, p := range path {
+		points = append(points, p.Geo())
+	}
+	c.include(points...)
+	style.Fill = ""
+	c.elements = append(c.elements, func(transform svgTransform) string {
+		var coords []string
+		for _, p := range points {
+			coords = append(coords, transform.point(p))
+		}
+		return fmt.Sprintf(`<polyline points="%s" %s/>`, strings.Join(coords, " "), style.attributes())
+	})
+}
+
+// AStarResult draws the path of the result of an A* search.
+func (c *SVGCanvas) AStarResult(r *AStarResult, style SVGStyle) {
+	c.Path(r.Path, style)
+}
+
+// SearchSnapshot draws the state of a search, e.g. as one frame of an
+// animation made with a SearchRecorder: the closed and open sets, and the
+// latest path.
+func (c *SVGCanvas) SearchSnapshot(s *SearchSnapshot, closed, open, path SVGStyle) {
+	c.HexSet(s.Closed, closed)
+	c.HexSet(s.Open, open)
+	if len(s.Path) > 0 {
+		c.Path(s.Path, path)
+	}
+}
+
+func arcPoints(center GeoCoord, r, a0, size float64) []GeoCoord {
+	steps := int(math.Ceil(size / svgWedgeStep))
+	if steps < 1 {
+		steps = 1
+	}
+	rv := make([]GeoCoord, steps+1)
+	for i := range rv {
+		a := a0 + size*float64(i)/float64(steps)
+		rv[i] = center.Add(NewGeoPolar(r, a))
+	}
+	return rv
+}
+
+// Wedge draws the part of an annulus around the centre of a hex (between
+// radii r0 and r1, in geometric units) that lies within an AngularInterval.
+func (c *SVGCanvas) Wedge(center HexCoord, n AngularInterval, r0, r1 float64, style SVGStyle) {
+	if n.Empty {
+		return
+	}
+
+	g := center.Geo()
+	a0, size := n.Rad0, n.Size()
+	if n.Full {
+		a0 = 0
+	}
+
+	outer := arcPoints(g, r1, a0, size)
+	inner := arcPoints(g, r0, a0, size)
+	c.include(outer...)
+
+	var rings [][]GeoCoord
+	switch {
+	case n.Full && r0 > 0:
+		rings = [][]GeoCoord{outer, inner}
+	case n.Full:
+		rings = [][]GeoCoord{outer}
+	case r0 > 0:
+		ring := outer
+		for i := len(inner) - 1; i >= 0; i-- {
+			ring = append(ring, inner[i])
+		}
+		rings = [][]GeoCoord{ring}
+	default:
+		rings = [][]GeoCoord{append(outer, g)}
+	}
+
+	c.elements = append(c.elements, func(transform svgTransform) string {
+		var d []string
+		for _, ring := range rings {
+			for i, p := range ring {
+				cmd := "L"
+				if i == 0 {
+					cmd = "M"
+				}
+				d = append(d, cmd+transform.point(p))
+			}
+			d = append(d, "Z")
+		}
+		return fmt.Sprintf(`<path d="%s" fill-rule="evenodd" %s/>`, strings.Join(d, " "), style.attributes())
+	})
+}
+
+// FovLight draws an angular interval of light falling on a hex, as passed to
+// the addLight callback of CalculateFov from a given origin. The light is
+// drawn as a wedge around the origin, spanning the distance to the hex.
+func (c *SVGCanvas) FovLight(origin, p HexCoord, n AngularInterval, style SVGStyle) {
+	d := p.Geo().DistanceTo(origin.Geo())
+	r0 := math.Max(0, d-1)
+	c.Wedge(origin, n, r0, d+1, style)
+}
+
+// Render writes the SVG image to a Writer.
+func (c *SVGCanvas) Render(w io.Writer) error {
+	min, max := c.min, c.max
+	width := (max.X-min.X)*c.Scale + 2*c.Margin
+	height := (max.Y-min.Y)*c.Scale + 2*c.Margin
+
+	transform := func(p GeoCoord) (float64, float64) {
+		return (p.X-min.X)*c.Scale + c.Margin, (max.Y-p.Y)*c.Scale + c.Margin
+	}
+
+	if _, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%.2f" height="%.2f" viewBox="0 0 %.2f %.2f">`+"\n", width, height, width, height); err != nil {
+		return err
+	}
+	for _, element := range c.elements {
+		if _, err := fmt.Fprintln(w, element(svgTransform(transform))); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "</svg>")
+	return err
+}
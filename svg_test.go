@@ -0,0 +1,75 @@
+package hex
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func countSVGElements(t *testing.T, svg string) map[string]int {
+	rv := map[string]int{}
+	d := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return rv
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, svg)
+		}
+		if el, ok := tok.(xml.StartElement); ok {
+			rv[el.Name.Local]++
+		}
+	}
+}
+
+func TestSVGCanvasRender(t *testing.T) {
+	c := NewSVGCanvas(10)
+	c.HexSet(NewHexSetAround(Origin, 1), SVGStyle{Fill: "#eee", Stroke: "black"})
+	c.Cells([]HexCoord{NewHex(0, 4)}, "black", func(p HexCoord) (string, string) {
+		return "red", "<a & b>"
+	})
+	c.AStarResult(&AStarResult{Path: []HexCoord{Origin, NewHex(0, 2), NewHex(0, 4)}}, SVGStyle{Stroke: "blue", StrokeWidth: 2})
+	c.Wedge(Origin, NewAngularInterval(0, 1), 0, 3, SVGStyle{Fill: "yellow", Opacity: 0.5})
+	c.FovLight(Origin, NewHex(0, 4), FullAngularInterval, SVGStyle{Fill: "yellow"})
+
+	var buf bytes.Buffer
+	if err := c.Render(&buf); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	svg := buf.String()
+
+	counts := countSVGElements(t, svg)
+	expect := map[string]int{"svg": 1, "polygon": 8, "text": 1, "polyline": 1, "path": 2}
+	for name, n := range expect {
+		if counts[name] != n {
+			t.Errorf("expected %d %s elements, got %d", n, name, counts[name])
+		}
+	}
+
+	if !strings.Contains(svg, "&lt;a &amp; b&gt;") {
+		t.Errorf("expected label to be escaped:\n%s", svg)
+	}
+}
+
+func TestSVGCanvasNorthIsUp(t *testing.T) {
+	c := NewSVGCanvas(10)
+	c.Margin = 0
+	c.Hex(Origin, SVGStyle{})
+	c.Label(NewHex(0, 2), "N")
+	c.Label(NewHex(0, -2), "S")
+
+	var buf bytes.Buffer
+	if err := c.Render(&buf); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	svg := buf.String()
+
+	north := strings.Index(svg, `y="0.00"`)
+	south := strings.Index(svg, `y="40.00"`)
+	if north < 0 || south < 0 || north > strings.Index(svg, ">N<") || south > strings.Index(svg, ">S<") {
+		t.Errorf("expected north label at top and south label at bottom:\n%s", svg)
+	}
+}
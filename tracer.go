@@ -0,0 +1,126 @@
+package hex
+
+// SearchTracer is notified of the progress of a search, for debugging or
+// visualisation. Push is called when a node is added to the open set (or its
+// cost is improved), Pop when it is taken from the open set, Expand when its
+// successors are generated, and Path when the path to a goal (or a partial
+// path) is reconstructed. Costs are path costs from the start, which for
+// BreadthFirstSearch and DepthFirstSearch are numbers of steps.
+type SearchTracer interface {
+	Push(p HexCoord, cost float64)
+	Pop(p HexCoord, cost float64)
+	Expand(p HexCoord)
+	Path(path []HexCoord)
+}
+
+type nullTracer[S comparable] struct{}
+
+func (nullTracer[S]) Push(S, float64) {}
+func (nullTracer[S]) Pop(S, float64)  {}
+func (nullTracer[S]) Expand(S)        {}
+func (nullTracer[S]) Path([]S)        {}
+
+func tracerOrNull[S comparable](t StateTracer[S]) StateTracer[S] {
+	if t == nil {
+		return nullTracer[S]{}
+	}
+	return t
+}
+
+// SearchEventKind is the kind of a SearchEvent, corresponding to the
+// methods of SearchTracer.
+type SearchEventKind int
+
+const (
+	SearchPush SearchEventKind = iota
+	SearchPop
+	SearchExpand
+	SearchPath
+)
+
+// SearchEvent is a single notification to a SearchTracer.
+type SearchEvent struct {
+	Kind  SearchEventKind
+	Point HexCoord
+	Cost  float64
+	Path  []HexCoord
+}
+
+// SearchRecorder is a SearchTracer that records every event of a search,
+// so that it can be replayed or rendered later.
+type SearchRecorder struct {
+	Events []SearchEvent
+}
+
+// Push records a SearchPush event.
+func (r *SearchRecorder) Push(p HexCoord, cost float64) {
+	r.Events = append(r.Events, SearchEvent{Kind: SearchPush, Point: p, Cost: cost})
+}
+
+// Pop records a SearchPop event.
+func (r *SearchRecorder) Pop(p HexCoord, cost float64) {
+	r.Events = append(r.Events, SearchEvent{Kind: SearchPop, Point: p, Cost: cost})
+}
+
+// Expand records a SearchExpand event.
+func (r *SearchRecorder) Expand(p HexCoord) {
+	r.Events = append(r.Events, SearchEvent{Kind: SearchExpand, Point: p})
+}
+
+// Path records a SearchPath event, with a copy of the path.
+func (r *SearchRecorder) Path(path []HexCoord) {
+	r.Events = append(r.Events, SearchEvent{Kind: SearchPath, Path: append([]HexCoord(nil), path...)})
+}
+
+// Replay sends the recorded events to another SearchTracer.
+func (r *SearchRecorder) Replay(t SearchTracer) {
+	for _, e := range r.Events {
+		switch e.Kind {
+		case SearchPush:
+			t.Push(e.Point, e.Cost)
+		case SearchPop:
+			t.Pop(e.Point, e.Cost)
+		case SearchExpand:
+			t.Expand(e.Point)
+		case SearchPath:
+			t.Path(e.Path)
+		}
+	}
+}
+
+// SearchSnapshot represents the state of a search at some point: the open
+// and closed sets, the best known costs, and the latest path.
+type SearchSnapshot struct {
+	Open   *HexSet
+	Closed *HexSet
+	Cost   HexMap[float64]
+	Path   []HexCoord
+}
+
+// Snapshot computes the state of the recorded search after its first n
+// events, e.g. to render one frame of an animation.
+func (r *SearchRecorder) Snapshot(n int) *SearchSnapshot {
+	rv := &SearchSnapshot{
+		Open:   NewHexSet(),
+		Closed: NewHexSet(),
+		Cost:   HexMap[float64]{},
+	}
+	if n > len(r.Events) {
+		n = len(r.Events)
+	}
+
+	for _, e := range r.Events[:n] {
+		switch e.Kind {
+		case SearchPush:
+			rv.Open.Add(e.Point)
+			rv.Cost[e.Point] = e.Cost
+		case SearchPop:
+			rv.Open.Remove(e.Point)
+			rv.Closed.Add(e.Point)
+		case SearchPath:
+			rv.Path = e.Path
+		}
+	}
+
+	return rv
+}
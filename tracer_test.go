@@ -0,0 +1,82 @@
+package hex
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSearchRecorderAStar(t *testing.T) {
+	params := benchmarkAStarParams(0.2)
+	recorder := &SearchRecorder{}
+	params.Tracer = recorder
+
+	result, err := AStar(params)
+	if err != nil {
+		t.Fatalf("A* failed: %v", err)
+	}
+
+	counts := map[SearchEventKind]int{}
+	for _, e := range recorder.Events {
+		counts[e.Kind]++
+	}
+	if counts[SearchPop] == 0 || counts[SearchPush] < counts[SearchPop] || counts[SearchExpand] != counts[SearchPop]-1 || counts[SearchPath] != 1 {
+		t.Errorf("unexpected event counts: %v", counts)
+	}
+
+	final := recorder.Snapshot(len(recorder.Events))
+	if !reflect.DeepEqual(final.Path, result.Path) {
+		t.Errorf("expected final snapshot to contain the path")
+	}
+	for _, p := range result.Path[:len(result.Path)-1] {
+		if !final.Closed.Contains(p) {
+			t.Errorf("expected %v on path to be closed", p)
+		}
+	}
+	if final.Cost[result.Path[len(result.Path)-1]] != result.Cost {
+		t.Errorf("expected snapshot to record cost of goal")
+	}
+
+	first := recorder.Snapshot(1)
+	if first.Open.Size() != 1 || first.Closed.Size() != 0 || first.Path != nil {
+		t.Errorf("expected first snapshot to contain only the start")
+	}
+
+	replayed := &SearchRecorder{}
+	recorder.Replay(replayed)
+	if !reflect.DeepEqual(recorder.Events, replayed.Events) {
+		t.Errorf("replay differs from recording")
+	}
+
+	canvas := NewSVGCanvas(10)
+	canvas.SearchSnapshot(recorder.Snapshot(len(recorder.Events)/2), SVGStyle{Fill: "grey"}, SVGStyle{Fill: "yellow"}, SVGStyle{Stroke: "red"})
+	var buf bytes.Buffer
+	if err := canvas.Render(&buf); err != nil || !strings.Contains(buf.String(), "yellow") {
+		t.Errorf("failed to render snapshot: %v", err)
+	}
+}
+
+func TestSearchRecorderBFS(t *testing.T) {
+	goal := NewHex(0, 6)
+	recorder := &SearchRecorder{}
+	path, err := BreadthFirstSearchWithParams(&SearchParams{
+		Start:       NewHexSetSingleton(Origin),
+		IsSteppable: func(_, _ HexCoord) bool { return true },
+		IsGoal:      func(p HexCoord) bool { return p == goal },
+		Tracer:      recorder,
+	})
+	if err != nil {
+		t.Fatalf("BFS failed: %v", err)
+	}
+
+	last := recorder.Events[len(recorder.Events)-1]
+	if last.Kind != SearchPath || !reflect.DeepEqual(last.Path, path) {
+		t.Errorf("expected last event to be the path")
+	}
+	for _, e := range recorder.Events {
+		if e.Kind == SearchPush && e.Cost != float64(e.Point.StepsTo(Origin)) {
+			t.Errorf("expected BFS cost of %v to be its depth, got %v", e.Point, e.Cost)
+		}
+	}
+}
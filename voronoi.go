@@ -0,0 +1,124 @@
+package hex
+
+import (
+	"fmt"
+)
+
+// VoronoiParams provides parameters for partitioning a region between seeds.
+// Hexes are assigned only if they are in Region (if set) and IsPassable (if
+// set); at least one of these must be set and bound the partition. Distances
+// are measured in steps, or with Cost if set (as for AStarParams).
+type VoronoiParams struct {
+	Seeds      []HexCoord
+	Region     *HexSet
+	IsPassable func(HexCoord) bool
+	Cost       func(HexCoord, HexCoord) (float64, bool)
+}
+
+// VoronoiEdge is an edge between two adjacent hexes with different owners.
+type VoronoiEdge struct {
+	A, B           HexCoord
+	OwnerA, OwnerB int
+}
+
+// VoronoiResult represents the result of a Voronoi partition. Owners are
+// indices into VoronoiParams.Seeds.
+type VoronoiResult struct {
+	Owner    HexMap[int]
+	Distance HexMap[float64]
+	Cells    []*HexSet
+	Edges    []VoronoiEdge
+}
+
+type voronoiNode struct {
+	point HexCoord
+	owner int
+	cost  float64
+}
+
+// VoronoiPartition assigns every reachable hex in a region to its nearest
+// seed. Ties are broken in favour of the seed listed first.
+func VoronoiPartition(params *VoronoiParams) (*VoronoiResult, error) {
+	if params.Region == nil && params.IsPassable == nil {
+		return nil, fmt.Errorf("voronoi partition needs a region or passability predicate")
+	}
+
+	contains := func(p HexCoord) bool {
+		if params.Region != nil && !params.Region.Contains(p) {
+			return false
+		}
+		return params.IsPassable == nil || params.IsPassable(p)
+	}
+
+	cost := params.Cost
+	if cost == nil {
+		cost = func(_, _ HexCoord) (float64, bool) { return 1, true }
+	}
+
+	rv := &VoronoiResult{
+		Owner:    HexMap[int]{},
+		Distance: HexMap[float64]{},
+	}
+
+	q := newPriorityQueue(func(a, b voronoiNode) bool {
+		if a.cost != b.cost {
+			return a.cost < b.cost
+		}
+		return a.owner < b.owner
+	})
+	seen := map[HexCoord]bool{}
+	for i, p := range params.Seeds {
+		if !contains(p) {
+			return nil, fmt.Errorf("seed %v is outside the region", p)
+		}
+		if seen[p] {
+			return nil, fmt.Errorf("seed %v is listed twice", p)
+		}
+		seen[p] = true
+		q.Push(voronoiNode{point: p, owner: i})
+		rv.Cells = append(rv.Cells, NewHexSet())
+	}
+
+	for q.Len() > 0 {
+		current := q.Pop()
+		if _, done := rv.Owner[current.point]; done {
+			continue
+		}
+
+		rv.Owner[current.point] = current.owner
+		rv.Distance[current.point] = current.cost
+		rv.Cells[current.owner].Add(current.point)
+
+		for _, nb := range current.point.Neighbours() {
+			if _, done := rv.Owner[nb]; done || !contains(nb) {
+				continue
+			}
+			stepCost, ok := cost(current.point, nb)
+			if !ok {
+				continue
+			}
+			q.Push(voronoiNode{
+				point: nb,
+				owner: current.owner,
+				cost:  current.cost + stepCost,
+			})
+		}
+	}
+
+	for _, p := range rv.Owner.Domain().ToOrderedList() {
+		for _, nb := range p.Neighbours() {
+			owner, ok := rv.Owner[nb]
+			if !ok || !p.Less(nb) || owner == rv.Owner[p] {
+				continue
+			}
+			rv.Edges = append(rv.Edges, VoronoiEdge{
+				A:      p,
+				B:      nb,
+				OwnerA: rv.Owner[p],
+				OwnerB: owner,
+			})
+		}
+	}
+
+	return rv, nil
+}
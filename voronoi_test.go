@@ -0,0 +1,97 @@
+package hex
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVoronoiPartitionBySteps(t *testing.T) {
+	region := NewHexSetAround(Origin, 6)
+	seeds := []HexCoord{NewHex(-2, 0), NewHex(2, 0), NewHex(0, 8)}
+
+	result, err := VoronoiPartition(&VoronoiParams{
+		Seeds:  seeds,
+		Region: region,
+	})
+	if err != nil {
+		t.Fatalf("VoronoiPartition failed: %v", err)
+	}
+
+	total := 0
+	for i, cell := range result.Cells {
+		total += cell.Size()
+		if !cell.Contains(seeds[i]) || !IsFullyConnected(cell) {
+			t.Errorf("cell %d does not contain its seed or is not connected", i)
+		}
+	}
+	if total != region.Size() || len(result.Owner) != region.Size() {
+		t.Errorf("expected all %d hexes to be assigned, got %d", region.Size(), total)
+	}
+
+	for p, owner := range result.Owner {
+		best := -1
+		for i, s := range seeds {
+			if best < 0 || p.StepsTo(s) < p.StepsTo(seeds[best]) {
+				best = i
+			}
+		}
+		if owner != best {
+			t.Errorf("%v assigned to seed %d, expected nearest (first) seed %d", p, owner, best)
+		}
+		if result.Distance[p] != float64(p.StepsTo(seeds[owner])) {
+			t.Errorf("wrong distance for %v: %v", p, result.Distance[p])
+		}
+	}
+
+	for _, e := range result.Edges {
+		if e.OwnerA == e.OwnerB || result.Owner[e.A] != e.OwnerA || result.Owner[e.B] != e.OwnerB || e.A.StepsTo(e.B) != 1 {
+			t.Errorf("invalid edge %+v", e)
+		}
+	}
+	if len(result.Edges) == 0 {
+		t.Errorf("expected boundary edges")
+	}
+
+	again, _ := VoronoiPartition(&VoronoiParams{Seeds: seeds, Region: region})
+	if !reflect.DeepEqual(result.Edges, again.Edges) {
+		t.Errorf("expected edges to be deterministic")
+	}
+}
+
+func TestVoronoiPartitionWeighted(t *testing.T) {
+	// Moving east is expensive, so the western seed's territory is smaller.
+	cost := func(a, b HexCoord) (float64, bool) {
+		if b.X > a.X {
+			return 3, true
+		}
+		return 1, true
+	}
+
+	result, err := VoronoiPartition(&VoronoiParams{
+		Seeds:  []HexCoord{NewHex(-4, 0), NewHex(4, 0)},
+		Region: NewHexSetAround(Origin, 8),
+		Cost:   cost,
+	})
+	if err != nil {
+		t.Fatalf("VoronoiPartition failed: %v", err)
+	}
+
+	if result.Cells[0].Size() >= result.Cells[1].Size() {
+		t.Errorf("expected western seed to own less, got %d vs %d", result.Cells[0].Size(), result.Cells[1].Size())
+	}
+	if owner := result.Owner[Origin]; owner != 1 {
+		t.Errorf("expected origin to be owned by the eastern seed, got %d", owner)
+	}
+}
+
+func TestVoronoiPartitionErrors(t *testing.T) {
+	if _, err := VoronoiPartition(&VoronoiParams{Seeds: []HexCoord{Origin}}); err == nil {
+		t.Errorf("expected error for unbounded partition")
+	}
+	if _, err := VoronoiPartition(&VoronoiParams{
+		Seeds:  []HexCoord{NewHex(0, 20)},
+		Region: NewHexSetAround(Origin, 2),
+	}); err == nil {
+		t.Errorf("expected error for seed outside region")
+	}
+}
@@ -0,0 +1,30 @@
+package hex
+
+// ZoneOfControl describes wargame zone-of-control rules. Entering a hex in
+// Hexes ends movement, so a path may only pass through such a hex if it
+// starts there. Leaving such a hex costs ExitCost extra, and unless
+// AllowZOCToZOC is set, a unit may not step directly from one such hex to
+// another.
+type ZoneOfControl struct {
+	Hexes         *HexSet
+	ExitCost      float64
+	AllowZOCToZOC bool
+}
+
+// CanLeave checks whether movement may continue from a hex, given whether
+// it is where movement started.
+func (z *ZoneOfControl) CanLeave(p HexCoord, isStart bool) bool {
+	return z == nil || isStart || !z.Hexes.Contains(p)
+}
+
+// StepCost computes the extra cost of a step under the zone-of-control
+// rules. The second return value is false if the step is not allowed.
+func (z *ZoneOfControl) StepCost(from, to HexCoord) (float64, bool) {
+	if z == nil || !z.Hexes.Contains(from) {
+		return 0, true
+	}
+	if !z.AllowZOCToZOC && z.Hexes.Contains(to) {
+		return 0, false
+	}
+	return z.ExitCost, true
+}
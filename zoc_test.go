@@ -0,0 +1,90 @@
+package hex
+
+import (
+	"testing"
+)
+
+func testZOC() (*ZoneOfControl, func(HexCoord, HexCoord) (float64, bool)) {
+	enemy := NewHex(2, 0)
+	zoc := &ZoneOfControl{Hexes: NewHexSet()}
+	for _, p := range enemy.Neighbours() {
+		zoc.Hexes.Add(p)
+	}
+	cost := func(a, b HexCoord) (float64, bool) {
+		return 1, b != enemy
+	}
+	return zoc, cost
+}
+
+func TestMovementRangeWithZOC(t *testing.T) {
+	zoc, cost := testZOC()
+	target := NewHex(3, 1)
+
+	m := Reachable(Origin, 4, cost)
+	if m.Remaining[target] != 1 {
+		t.Errorf("expected %v to be reachable in 3 steps without ZOC, got %v", target, m.Remaining[target])
+	}
+
+	m = FindMovementRange(&MovementRangeParams{Start: Origin, MovementPoints: 4, Cost: cost, ZOC: zoc})
+	if _, ok := m.Remaining[target]; ok {
+		t.Errorf("expected %v to be unreachable in 4 steps with ZOC", target)
+	}
+	if m.Remaining[NewHex(1, 1)] != 3 {
+		t.Errorf("expected to be able to enter ZOC")
+	}
+
+	m = FindMovementRange(&MovementRangeParams{Start: Origin, MovementPoints: 5, Cost: cost, ZOC: zoc})
+	path, err := m.PathTo(target)
+	if err != nil || len(path) != 6 {
+		t.Fatalf("expected path of 5 steps around ZOC, got %v (%v)", path, err)
+	}
+	for _, p := range path[:len(path)-1] {
+		if zoc.Hexes.Contains(p) {
+			t.Errorf("path %v continues through ZOC at %v", path, p)
+		}
+	}
+}
+
+func TestMovementRangeLeavingZOC(t *testing.T) {
+	zoc, cost := testZOC()
+	zoc.ExitCost = 1
+	start := NewHex(1, 1)
+
+	m := FindMovementRange(&MovementRangeParams{Start: start, MovementPoints: 2, Cost: cost, ZOC: zoc})
+	if m.Remaining[NewHex(0, 2)] != 0 {
+		t.Errorf("expected leaving ZOC to cost 2, got remaining %v", m.Remaining[NewHex(0, 2)])
+	}
+	if _, ok := m.Remaining[NewHex(2, 2)]; ok {
+		t.Errorf("expected ZOC-to-ZOC move to be forbidden")
+	}
+
+	zoc.AllowZOCToZOC = true
+	m = FindMovementRange(&MovementRangeParams{Start: start, MovementPoints: 2, Cost: cost, ZOC: zoc})
+	if _, ok := m.Remaining[NewHex(2, 2)]; !ok {
+		t.Errorf("expected ZOC-to-ZOC move to be allowed")
+	}
+}
+
+func TestAStarWithZOC(t *testing.T) {
+	zoc, cost := testZOC()
+	target := NewHex(3, 1)
+
+	result, err := AStar(&AStarParams{
+		Start:     NewHexSetSingleton(Origin),
+		IsGoal:    func(p HexCoord) bool { return p == target },
+		Cost:      cost,
+		Heuristic: func(p HexCoord) float64 { return float64(p.StepsTo(target)) },
+		ZOC:       zoc,
+	})
+	if err != nil {
+		t.Fatalf("A* failed: %v", err)
+	}
+	if result.Cost != 5 {
+		t.Errorf("expected cost 5 around ZOC, got %v: %v", result.Cost, result.Path)
+	}
+	for _, p := range result.Path[:len(result.Path)-1] {
+		if zoc.Hexes.Contains(p) {
+			t.Errorf("path %v continues through ZOC at %v", result.Path, p)
+		}
+	}
+}